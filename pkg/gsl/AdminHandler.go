@@ -0,0 +1,227 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AdminStatus is the JSON document returned by the handler created by AdminHandler.
+type AdminStatus struct {
+	MinLevel        string         `json:"minLevel"`           // the minimum level
+	Levels          map[string]int `json:"levels"`             // level --> position in writers
+	Formats         []string       `json:"formats"`            // list of formats for each writer
+	LevelField      string         `json:"levelField"`         // the key for the level field
+	TimeStampField  string         `json:"timeStampField"`     // the key for the timestamp field
+	TimeStampFormat string         `json:"timeStampFormat"`    // the format for the timestamp field
+	MessageField    string         `json:"messageField"`       // the key for the message field
+	AutoFlush       bool           `json:"autoFlush"`          // flush after every message
+	RevertAt        *time.Time     `json:"revertAt,omitempty"` // when a temporary update is reverted
+}
+
+// AdminUpdate is the JSON document accepted by the handler created by AdminHandler.
+// Levels maps a level to a position in writers.  A negative position disables the level.
+// If Duration is set, e.g., "10m", then the changes are reverted once the duration has elapsed.
+type AdminUpdate struct {
+	MinLevel string         `json:"minLevel,omitempty"` // the new minimum level
+	Levels   map[string]int `json:"levels,omitempty"`   // level --> position in writers
+	Duration string         `json:"duration,omitempty"` // revert the changes after the duration
+}
+
+// adminSnapshot is the state of the logger before a temporary update.
+type adminSnapshot struct {
+	minLevel Level
+	routes   map[string]route
+}
+
+type adminHandler struct {
+	logger    *Logger
+	mutex     sync.Mutex
+	afterFunc func(d time.Duration, f func()) *time.Timer // starts the timer for reverting, defaults to time.AfterFunc
+	timer     *time.Timer                                 // timer for reverting a temporary update
+	snapshot  *adminSnapshot                              // state to revert to
+	revertAt  time.Time
+}
+
+// AdminHandler returns an http.Handler for inspecting and changing the configuration of the logger while running.
+// A GET request returns the current configuration as an AdminStatus JSON document.
+// A PUT or POST request applies an AdminUpdate JSON document and returns the new configuration.
+// If the update includes a duration, then the changes are reverted automatically once the duration has elapsed.
+// A new update cancels a pending revert, restoring the previous configuration before applying the new update.
+func AdminHandler(logger *Logger) http.Handler {
+	return &adminHandler{logger: logger, afterFunc: time.AfterFunc}
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.writeStatus(w, http.StatusOK)
+	case http.MethodPut, http.MethodPost:
+		update := &AdminUpdate{}
+		err := json.NewDecoder(r.Body).Decode(update)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("error decoding update: %v", err))
+			return
+		}
+		err = h.apply(update)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.writeStatus(w, http.StatusOK)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// apply validates and applies the update to the logger.
+// If the update is invalid, then the logger is not changed.
+func (h *adminHandler) apply(update *AdminUpdate) error {
+	var duration time.Duration
+	if len(update.Duration) > 0 {
+		d, err := time.ParseDuration(update.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %v", update.Duration, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid duration %q: duration must be positive", update.Duration)
+		}
+		duration = d
+	}
+
+	var minLevel *Level
+	if len(update.MinLevel) > 0 {
+		level, err := ParseLevel(update.MinLevel)
+		if err != nil {
+			return err
+		}
+		minLevel = &level
+	}
+
+	levels := make(map[string]int, len(update.Levels))
+	for name, position := range update.Levels {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		levels[level.String()] = position
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r := h.logger.router
+	r.Lock()
+	defer r.Unlock()
+
+	for _, position := range levels {
+		if position >= len(r.writers) {
+			return &ErrInvalidPosition{Position: position, Writers: len(r.writers)}
+		}
+	}
+
+	h.revertLocked()
+
+	snapshot := &adminSnapshot{minLevel: r.minLevel, routes: map[string]route{}}
+	for name := range levels {
+		snapshot.routes[name] = r.route(name)
+	}
+
+	if minLevel != nil {
+		r.minLevel = *minLevel
+	}
+	for name, position := range levels {
+		if position < 0 {
+			// remember the position, like DisableLevel, so the level can be re-enabled with EnableLevel
+			if rt := r.route(name); rt.enabled {
+				r.setRoute(name, route{position: rt.position, disabled: true})
+			}
+		} else {
+			r.setRoute(name, route{position: position, enabled: true})
+		}
+	}
+
+	if duration > 0 {
+		h.snapshot = snapshot
+		h.revertAt = time.Now().Add(duration)
+		var timer *time.Timer
+		timer = h.afterFunc(duration, func() {
+			h.mutex.Lock()
+			if h.timer == timer {
+				h.revert()
+			}
+			h.mutex.Unlock()
+		})
+		h.timer = timer
+	}
+
+	return nil
+}
+
+// revert restores the logger to the state before the pending temporary update, if any.
+// The caller must hold the handler mutex.
+func (h *adminHandler) revert() {
+	h.logger.router.Lock()
+	h.revertLocked()
+	h.logger.router.Unlock()
+}
+
+// revertLocked restores the logger to the state before the pending temporary update, if any.
+// Routes to positions that no longer exist, e.g., after ApplyConfig removed writers, are not restored.
+// The caller must hold the handler mutex and the router lock.
+func (h *adminHandler) revertLocked() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if h.snapshot == nil {
+		return
+	}
+	r := h.logger.router
+	r.minLevel = h.snapshot.minLevel
+	for name, rt := range h.snapshot.routes {
+		if (rt.enabled || rt.disabled) && rt.position >= len(r.writers) {
+			continue
+		}
+		r.setRoute(name, rt)
+	}
+	h.snapshot = nil
+}
+
+func (h *adminHandler) writeStatus(w http.ResponseWriter, code int) {
+	status := &AdminStatus{
+		MinLevel:        h.logger.MinLevel().String(),
+		Levels:          h.logger.Levels(),
+		Formats:         h.logger.Formats(),
+		LevelField:      h.logger.LevelField,
+		TimeStampField:  h.logger.TimeStampField,
+		TimeStampFormat: h.logger.TimeStampFormat,
+		MessageField:    h.logger.MessageField,
+		AutoFlush:       h.logger.AutoFlush,
+	}
+	h.mutex.Lock()
+	if h.snapshot != nil {
+		revertAt := h.revertAt
+		status.RevertAt = &revertAt
+	}
+	h.mutex.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status) // #nosec
+}
+
+func (h *adminHandler) writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}) // #nosec
+}
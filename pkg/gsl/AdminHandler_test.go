@@ -0,0 +1,186 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+// captureRevert replaces the timer of the handler, so the test can revert a temporary update without waiting.
+// The returned function runs the revert scheduled by the last update.
+func captureRevert(h http.Handler) *func() {
+	revert := func() {}
+	h.(*adminHandler).afterFunc = func(d time.Duration, f func()) *time.Timer {
+		revert = f
+		return time.NewTimer(time.Hour)
+	}
+	return &revert
+}
+
+func TestAdminHandler(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0, "error": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+	l.SetMinLevel(LevelInfo)
+
+	h := AdminHandler(l)
+	revert := captureRevert(h)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	status := &AdminStatus{}
+	err := json.Unmarshal(rec.Body.Bytes(), status)
+	assert.NoError(t, err)
+	assert.Equal(t, "info", status.MinLevel)
+	assert.Equal(t, levels, status.Levels)
+	assert.Equal(t, formats, status.Formats)
+	assert.Equal(t, "msg", status.MessageField)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"minLevel":"debug","levels":{"debug":0},"duration":"10m"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, LevelDebug, l.MinLevel())
+	assert.True(t, l.Enabled(LevelDebug))
+
+	(*revert)()
+
+	assert.Equal(t, LevelInfo, l.MinLevel())
+	assert.False(t, l.Enabled(LevelDebug))
+	assert.Equal(t, levels, l.Levels())
+}
+
+func TestAdminHandlerInvalid(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, true)
+
+	h := AdminHandler(l)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"levels":{"debug":3}}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, map[string]int{"info": 0}, l.Levels())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"minLevel":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAdminHandlerInvalidDuringUpdate(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, true)
+	l.SetMinLevel(LevelInfo)
+
+	h := AdminHandler(l)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"minLevel":"debug","levels":{"debug":0},"duration":"1h"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"levels":{"warn":3}}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// the timed update is still active
+	assert.Equal(t, LevelDebug, l.MinLevel())
+	assert.Equal(t, map[string]int{"info": 0, "debug": 0}, l.Levels())
+
+	status := &AdminStatus{}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	err := json.Unmarshal(rec.Body.Bytes(), status)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, status.RevertAt)
+}
+
+func TestAdminHandlerDisable(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, true)
+
+	h := AdminHandler(l)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"levels":{"info":-1}}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, l.Enabled(LevelInfo))
+
+	// a level disabled over HTTP can be re-enabled like a level disabled with DisableLevel
+	err := l.EnableLevel(LevelInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"info": 0, "error": 0}, l.Levels())
+}
+
+func TestAdminHandlerRevertAfterApplyConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	a, _ := grw.WriteMemoryBytes()
+	b, _ := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "warn": 1, "error": 1}, []Writer{a, b}, []string{"json", "json"}, true)
+	l.DisableLevel(LevelWarn)
+
+	h := AdminHandler(l)
+	revert := captureRevert(h)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"levels":{"error":0,"warn":0},"duration":"10m"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// the new configuration only has one writer, so the routes to position 1 in the snapshot no longer exist
+	err = l.ApplyConfig(&Config{
+		Writers: []WriterConfig{{Name: "main", Destination: filepath.Join(dir, "main.log"), Format: "json"}},
+		Levels:  map[string]string{"info": "main", "error": "main"},
+	})
+	assert.NoError(t, err)
+
+	(*revert)()
+
+	assert.Equal(t, map[string]int{"info": 0, "error": 0}, l.Levels())
+	err = l.Error(testMessage)
+	assert.NoError(t, err)
+
+	l.router.Lock()
+	l.router.disabled["warn"] = 1
+	l.router.Unlock()
+	err = l.EnableLevel(LevelWarn)
+	assert.IsType(t, &ErrInvalidPosition{}, err)
+
+	l.Close()
+}
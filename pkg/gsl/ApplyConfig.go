@@ -0,0 +1,159 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+// ApplyConfig atomically replaces the writers, formats, level routing, and minimum level of the logger with those in the configuration.
+// Writers with the same destination and compression as an existing writer are reused, so no messages are lost.
+// New writers are created before the logger is locked, so messages are not blocked while files are opened,
+// and the logger is only locked to swap in the new configuration.
+// Existing writers that are no longer referenced are flushed and closed once the messages being written to them are complete.
+// The field keys and auto-flush setting are not changed, since they are not shared with child loggers.
+// If the configuration is invalid or a writer cannot be created, then returns an ErrInvalidConfig error and the logger is not changed.
+func (l *Logger) ApplyConfig(c *Config) error {
+	err := c.Validate()
+	if err != nil {
+		return err
+	}
+
+	levels := make(map[string]int, len(c.Levels))
+	positions := make(map[string]int, len(c.Writers))
+	formats := make([]string, 0, len(c.Writers))
+	destinations := make([]destination, 0, len(c.Writers))
+	for i, wc := range c.Writers {
+		positions[wc.Name] = i
+		formats = append(formats, wc.Format)
+		destinations = append(destinations, destination{uri: wc.Destination, compression: wc.Compression})
+	}
+	for name, writer := range c.Levels {
+		level, _ := ParseLevel(name) // already validated
+		levels[level.String()] = positions[writer]
+	}
+
+	minLevel := LevelTrace
+	if len(c.MinLevel) > 0 {
+		minLevel, _ = ParseLevel(c.MinLevel) // already validated
+	}
+
+	r := l.router
+	for {
+		// Match the new writers to the existing writers and open the writers that cannot be reused without holding the lock.
+		r.RLock()
+		existing := make([]destination, len(r.destinations))
+		copy(existing, r.destinations)
+		r.RUnlock()
+
+		reused := matchDestinations(existing, destinations)
+		created := make(map[int]Writer, len(destinations))
+		for i, wc := range c.Writers {
+			if _, ok := reused[i]; ok {
+				continue
+			}
+			w, err := grw.WriteToResource(&grw.WriteToResourceInput{
+				Uri:      wc.Destination,
+				Alg:      wc.Compression,
+				Dict:     grw.NoDict,
+				Append:   wc.Append == nil || *wc.Append,
+				S3Client: nil,
+			})
+			if err != nil {
+				for _, w := range created {
+					w.Close() // #nosec
+				}
+				return &ErrInvalidConfig{
+					Path: fmt.Sprintf("writers[%d].destination", i),
+					Err:  &ErrWriterCreate{Destination: wc.Destination, Err: err},
+				}
+			}
+			created[i] = w
+		}
+
+		r.Lock()
+		if !equalDestinations(existing, r.destinations) {
+			// the writers were replaced while the new writers were opened, so match them again
+			r.Unlock()
+			for _, w := range created {
+				w.Close() // #nosec
+			}
+			continue
+		}
+
+		used := make(map[int]bool, len(reused))
+		writers := make([]Writer, 0, len(destinations))
+		for i := range destinations {
+			if j, ok := reused[i]; ok {
+				used[j] = true
+				writers = append(writers, r.writers[j])
+			} else {
+				writers = append(writers, r.wrap(created[i]))
+			}
+		}
+
+		unused := make([]Writer, 0)
+		for i, w := range r.writers {
+			if !used[i] {
+				unused = append(unused, w)
+			}
+		}
+
+		r.levels = levels
+		r.disabled = map[string]int{}
+		r.writers = writers
+		r.formats = formats
+		r.destinations = destinations
+		r.minLevel = minLevel
+		r.verboseLevels = map[string]int{}
+		r.Unlock()
+
+		for _, w := range unused {
+			w.Lock()
+			w.Flush() // #nosec
+			w.Close() // #nosec
+			w.Unlock()
+		}
+
+		return nil
+	}
+}
+
+// matchDestinations returns the position of the existing writer reused for each new destination.
+// Each existing writer is reused at most once and writers without a destination are never reused.
+func matchDestinations(existing []destination, destinations []destination) map[int]int {
+	available := make(map[destination][]int, len(existing))
+	for j, d := range existing {
+		if len(d.uri) > 0 {
+			available[d] = append(available[d], j)
+		}
+	}
+	reused := map[int]int{}
+	for i, d := range destinations {
+		if js := available[d]; len(js) > 0 {
+			reused[i] = js[0]
+			available[d] = js[1:]
+		}
+	}
+	return reused
+}
+
+// equalDestinations returns true if the two lists of destinations are the same.
+func equalDestinations(a []destination, b []destination) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
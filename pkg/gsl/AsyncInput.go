@@ -0,0 +1,23 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+const (
+	DefaultAsyncSize = 1024 // the default number of messages queued for each writer in async mode
+)
+
+// AsyncInput configures the async mode of a logger.
+// If Priority is true, then each level has its own queue of Size messages and the overflow policy applies to each queue,
+// so error messages are written before queued debug and info messages and are not blocked or dropped because of them.
+// The messages at each level are always written in order.
+type AsyncInput struct {
+	Size      int            // the maximum number of messages queued for each writer, defaults to DefaultAsyncSize
+	Policy    OverflowPolicy // what happens when the queue is full
+	DropLevel Level          // the level at or above which messages are never dropped by OverflowDropBelow
+	Priority  bool           // queue each level separately and write the messages at the most severe level first
+}
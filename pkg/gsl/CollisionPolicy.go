@@ -0,0 +1,17 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// CollisionPolicy determines what happens when a logged object contains a key used by the logger for the level or timestamp field.
+type CollisionPolicy int
+
+const (
+	CollisionOverwrite CollisionPolicy = iota // the logger's value overwrites the user's value
+	CollisionPrefix                           // the user's value is written under the key prefixed with the fields key, e.g., "fields.level"
+	CollisionNest                             // the user's value is written in a map under the fields key, e.g., {"fields": {"level": ...}}
+)
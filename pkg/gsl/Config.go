@@ -0,0 +1,148 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/spatialcurrent/go-simple-serializer/pkg/gss"
+)
+
+// Config is a declarative configuration for a logger.
+// Use ParseConfig or LoadConfig to read a configuration from JSON, YAML, or TOML and NewLoggerFromConfig to create the logger.
+// The keys in the configuration are the kebab-case names of the fields, e.g., "min-level" for MinLevel.
+//
+// An example configuration in YAML is below.
+//
+//	writers:
+//	  - name: stderr
+//	    destination: stderr
+//	    format: json
+//	  - name: debug
+//	    destination: debug.log.gz
+//	    compression: gzip
+//	    format: tags
+//	levels:
+//	  debug: debug
+//	  info: stderr
+//	  error: stderr
+//	min-level: info
+type Config struct {
+	Writers         []WriterConfig    // list of writers
+	Levels          map[string]string // level --> writer name
+	MinLevel        string            // the minimum level, defaults to trace
+	LevelField      string            // the key for the level field, defaults to "level"
+	TimeStampField  string            // the key for the timestamp field, defaults to "ts"
+	TimeStampFormat string            // the format for the timestamp field, defaults to RFC 3339
+	MessageField    string            // the key for the message field, defaults to "msg"
+	AutoFlush       *bool             // flush after every message, defaults to true
+}
+
+// WriterConfig is the configuration for a single writer within a Config.
+type WriterConfig struct {
+	Name        string // the name of the writer referenced by levels
+	Destination string // the uri of the destination, e.g., "stderr" or "app.log"
+	Format      string // the format of the messages, e.g., "json"
+	Compression string // the compression algorithm, e.g., "gzip"
+	Append      *bool  // append to an existing file, defaults to true
+}
+
+// ParseConfig parses a configuration from the given bytes in the given format, e.g., "json", "yaml", or "toml".
+// The configuration is deserialized using go-simple-serializer and then validated.
+// If the configuration is invalid, then returns an ErrInvalidConfig error that points to the offending value.
+func ParseConfig(b []byte, format string) (*Config, error) {
+	obj, err := gss.DeserializeBytes(&gss.DeserializeBytesInput{
+		Bytes:             b,
+		Format:            format,
+		Header:            gss.NoHeader,
+		Limit:             gss.NoLimit,
+		Type:              reflect.TypeOf(map[string]interface{}{}),
+		LineSeparator:     "\n",
+		KeyValueSeparator: "=",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error deserializing config using format %s", format))
+	}
+	c, err := decodeConfig(obj)
+	if err != nil {
+		return nil, err
+	}
+	err = c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadConfig reads and parses the configuration in the file at the given path.
+// The format is inferred from the file extension: ".json", ".yaml", ".yml", or ".toml".
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading config from %s", path))
+	}
+	return parseConfigFile(path, b)
+}
+
+// configFormat returns the format of a configuration file given its path.
+func configFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	case ".toml":
+		return "toml", nil
+	}
+	return "", fmt.Errorf("cannot infer config format from path %s, expecting a .json, .yaml, .yml, or .toml extension", path)
+}
+
+// Validate returns an ErrInvalidConfig error if the configuration is invalid, otherwise returns nil.
+func (c *Config) Validate() error {
+	names := map[string]int{}
+	for i, w := range c.Writers {
+		path := fmt.Sprintf("writers[%d]", i)
+		if len(w.Name) == 0 {
+			return &ErrInvalidConfig{Path: path + ".name", Err: errors.New("missing name")}
+		}
+		if j, ok := names[w.Name]; ok {
+			return &ErrInvalidConfig{Path: path + ".name", Err: fmt.Errorf("name %q is already used by writers[%d]", w.Name, j)}
+		}
+		names[w.Name] = i
+		if len(w.Destination) == 0 {
+			return &ErrInvalidConfig{Path: path + ".destination", Err: errors.New("missing destination")}
+		}
+		if err := ValidateFormat(w.Format); err != nil {
+			return &ErrInvalidConfig{Path: path + ".format", Err: err}
+		}
+		if err := ValidateCompression(w.Compression); err != nil {
+			return &ErrInvalidConfig{Path: path + ".compression", Err: err}
+		}
+	}
+	for level, name := range c.Levels {
+		path := "levels." + level
+		if _, err := ParseLevel(level); err != nil {
+			return &ErrInvalidConfig{Path: path, Err: err}
+		}
+		if _, ok := names[name]; !ok {
+			return &ErrInvalidConfig{Path: path, Err: fmt.Errorf("unknown writer %q", name)}
+		}
+	}
+	if len(c.MinLevel) > 0 {
+		if _, err := ParseLevel(c.MinLevel); err != nil {
+			return &ErrInvalidConfig{Path: "min-level", Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `{
+  "writers": [
+    {"name": "error", "destination": "error.log", "format": "json"},
+    {"name": "debug", "destination": "debug.log", "format": "tags", "append": false}
+  ],
+  "levels": {"debug": "debug", "info": "error", "error": "error"},
+  "min-level": "info",
+  "message-field": "message",
+  "auto-flush": false
+}`
+
+const testConfigYAML = `
+writers:
+  - name: error
+    destination: error.log
+    format: json
+  - name: debug
+    destination: debug.log
+    format: tags
+    append: false
+levels:
+  debug: debug
+  info: error
+  error: error
+min-level: info
+message-field: message
+auto-flush: false
+`
+
+const testConfigTOML = `
+min-level = "info"
+message-field = "message"
+auto-flush = false
+
+[levels]
+debug = "debug"
+info = "error"
+error = "error"
+
+[[writers]]
+name = "error"
+destination = "error.log"
+format = "json"
+
+[[writers]]
+name = "debug"
+destination = "debug.log"
+format = "tags"
+append = false
+`
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		config string
+		format string
+	}{
+		{config: testConfig, format: "json"},
+		{config: testConfigYAML, format: "yaml"},
+		{config: testConfigTOML, format: "toml"},
+	}
+	for _, testCase := range testCases {
+		c, err := ParseConfig([]byte(testCase.config), testCase.format)
+		if !assert.NoError(t, err, testCase.format) {
+			continue
+		}
+		assert.Len(t, c.Writers, 2, testCase.format)
+		assert.Equal(t, "debug", c.Writers[1].Name, testCase.format)
+		assert.Equal(t, "tags", c.Writers[1].Format, testCase.format)
+		if assert.NotNil(t, c.Writers[1].Append, testCase.format) {
+			assert.False(t, *c.Writers[1].Append, testCase.format)
+		}
+		assert.Nil(t, c.Writers[0].Append, testCase.format)
+		assert.Equal(t, map[string]string{"debug": "debug", "info": "error", "error": "error"}, c.Levels, testCase.format)
+		assert.Equal(t, "info", c.MinLevel, testCase.format)
+		assert.Equal(t, "message", c.MessageField, testCase.format)
+		if assert.NotNil(t, c.AutoFlush, testCase.format) {
+			assert.False(t, *c.AutoFlush, testCase.format)
+		}
+	}
+}
+
+func TestParseConfigDefaults(t *testing.T) {
+	testCases := []struct {
+		config string
+		format string
+	}{
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json"}], "levels": {"info": "a"}}`, format: "json"},
+		{config: "writers:\n  - name: a\n    destination: stderr\n    format: json\nlevels:\n  info: a\n", format: "yaml"},
+		{config: "[levels]\ninfo = \"a\"\n\n[[writers]]\nname = \"a\"\ndestination = \"stderr\"\nformat = \"json\"\n", format: "toml"},
+	}
+	for _, testCase := range testCases {
+		c, err := ParseConfig([]byte(testCase.config), testCase.format)
+		if !assert.NoError(t, err, testCase.format) {
+			continue
+		}
+		assert.Equal(t, []WriterConfig{{Name: "a", Destination: "stderr", Format: "json"}}, c.Writers, testCase.format)
+		assert.Equal(t, map[string]string{"info": "a"}, c.Levels, testCase.format)
+		assert.Empty(t, c.MinLevel, testCase.format)
+		assert.Empty(t, c.MessageField, testCase.format)
+		assert.Nil(t, c.AutoFlush, testCase.format)
+
+		l, err := NewLoggerFromConfig(c)
+		if assert.NoError(t, err, testCase.format) {
+			assert.Equal(t, LevelTrace, l.MinLevel(), testCase.format)
+			assert.Equal(t, "msg", l.MessageField, testCase.format)
+			assert.Equal(t, "level", l.LevelField, testCase.format)
+			assert.True(t, l.AutoFlush, testCase.format)
+		}
+	}
+}
+
+func TestDecodeConfigInterfaceKeys(t *testing.T) {
+	// YAML decoders, such as gopkg.in/yaml.v2, return nested maps with interface{} keys
+	obj := map[string]interface{}{
+		"writers": []interface{}{
+			map[interface{}]interface{}{"name": "a", "destination": "stderr", "format": "json", "append": true},
+		},
+		"levels": map[interface{}]interface{}{"info": "a"},
+	}
+	c, err := decodeConfig(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", c.Writers[0].Name)
+	assert.True(t, *c.Writers[0].Append)
+	assert.Equal(t, map[string]string{"info": "a"}, c.Levels)
+
+	obj["levels"] = map[interface{}]interface{}{1: "a"}
+	_, err = decodeConfig(obj)
+	if assert.IsType(t, &ErrInvalidConfig{}, err) {
+		assert.Equal(t, "levels", err.(*ErrInvalidConfig).Path)
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	testCases := []struct {
+		config string
+		path   string
+	}{
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "xml"}]}`, path: "writers[0].format"},
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json", "compression": "lz4"}]}`, path: "writers[0].compression"},
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json", "append": "yes"}]}`, path: "writers[0].append"},
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json"}, {"name": "a", "destination": "stdout", "format": "json"}]}`, path: "writers[1].name"},
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json"}], "levels": {"info": "b"}}`, path: "levels.info"},
+		{config: `{"writers": [{"name": "a", "destination": "stderr", "format": "json"}], "levels": {"verbose": "a"}}`, path: "levels.verbose"},
+		{config: `{"min-level": "loud"}`, path: "min-level"},
+		{config: `{"colors": true}`, path: "colors"},
+	}
+	for _, testCase := range testCases {
+		_, err := ParseConfig([]byte(testCase.config), "json")
+		if assert.IsType(t, &ErrInvalidConfig{}, err, testCase.config) {
+			assert.Equal(t, testCase.path, err.(*ErrInvalidConfig).Path)
+		}
+	}
+}
+
+func TestNewLoggerFromConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	c, err := ParseConfig([]byte(testConfig), "json")
+	assert.NoError(t, err)
+	c.Writers[0].Destination = filepath.Join(dir, c.Writers[0].Destination)
+	c.Writers[1].Destination = filepath.Join(dir, c.Writers[1].Destination)
+
+	l, err := NewLoggerFromConfig(c)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"debug": 1, "info": 0, "error": 0}, l.Levels())
+	assert.Equal(t, []string{"json", "tags"}, l.Formats())
+	assert.Equal(t, LevelInfo, l.MinLevel())
+	assert.Equal(t, "message", l.MessageField)
+	assert.False(t, l.AutoFlush)
+	l.Close()
+}
@@ -0,0 +1,61 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"context"
+)
+
+// contextKey is the type of the keys used to store values in a context.Context.
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	fieldsContextKey
+)
+
+// NewContext returns a copy of the given context that carries the given logger.
+// Use FromContext to retrieve the logger downstream.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// FromContext returns the logger stored in the given context by NewContext.
+// If the context does not carry a logger, then returns nil.
+// Any fields attached to the context using WithFields are bound to the returned logger.
+func FromContext(ctx context.Context) *Logger {
+	logger, ok := ctx.Value(loggerContextKey).(*Logger)
+	if !ok || logger == nil {
+		return nil
+	}
+	return logger.withContext(ctx)
+}
+
+// WithFields returns a copy of the given context that carries the given fields along with any fields already attached to the context.
+// The fields are included in every message written using the context-aware logging methods, e.g., InfoContext.
+func WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	parent := FieldsFromContext(ctx)
+	m := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		m[k] = v
+	}
+	for k, v := range fields {
+		m[k] = v
+	}
+	return context.WithValue(ctx, fieldsContextKey, m)
+}
+
+// FieldsFromContext returns the fields attached to the given context by WithFields.
+// If the context does not carry any fields, then returns nil.
+// The returned map should not be modified.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if fields, ok := ctx.Value(fieldsContextKey).(map[string]interface{}); ok {
+		return fields
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+func TestLoggerInfoContext(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	ctx := WithFields(context.Background(), map[string]interface{}{"trace_id": "123"})
+	ctx = WithFields(ctx, map[string]interface{}{"request_id": "abc"})
+	ctx = NewContext(ctx, l)
+
+	err := FromContext(ctx).InfoContext(ctx, testMessage)
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "info", outObject["level"])
+	assert.Equal(t, testMessage, outObject["msg"])
+	assert.Equal(t, "123", outObject["trace_id"])
+	assert.Equal(t, "abc", outObject["request_id"])
+}
+
+func TestFromContextMissing(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+}
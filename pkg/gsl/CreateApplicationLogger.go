@@ -10,13 +10,11 @@ package gsl
 import (
 	"fmt"
 	"os"
-
-	"github.com/pkg/errors"
-
-	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
 )
 
 // CreateApplicationLoggerInput holds the input for the CreateApplicationLogger function..
+// If Destinations is set, then each level is written to its own destination and the Error* and Info* fields are ignored.
+// Otherwise, error, fatal, and warn messages are written to the error destination and info and debug messages are written to the info destination.
 type CreateApplicationLoggerInput struct {
 	ErrorDestination string
 	ErrorCompression string
@@ -24,6 +22,7 @@ type CreateApplicationLoggerInput struct {
 	InfoDestination  string
 	InfoCompression  string
 	InfoFormat       string
+	Destinations     []LevelDestination // per-level destinations
 	Verbose          bool
 }
 
@@ -31,76 +30,51 @@ type CreateApplicationLoggerInput struct {
 // This function creates a logger that intuitively works as you would expect an application logger to work.
 // The logger shares a single grw.ByteWriteCloser if error and info messages are going to the same location.
 // If verbose mode is on, warn messages are sent to the error log and debug messages are sent to the info log.
+// If verbose mode is off, the minimum level is set to info, so debug and trace messages are dropped silently.
+// When using per-level destinations, verbose mode only changes the minimum level.
+// Fatal messages are written to the error destination and the fatal destination, if different.
+// Verbose mode can be changed later using SetVerbose or HandleSignals.
 // If there is an error during creation then the program prints the error and exits with exit code 1.
+// Use NewApplicationLogger to handle the error instead.
 func CreateApplicationLogger(input *CreateApplicationLoggerInput) *Logger {
-
-	errorWriter, err := grw.WriteToResource(&grw.WriteToResourceInput{
-		Uri:      input.ErrorDestination,
-		Alg:      input.ErrorCompression,
-		Dict:     grw.NoDict,
-		Append:   true,
-		S3Client: nil,
-	})
+	logger, err := NewApplicationLogger(input)
 	if err != nil {
-		fmt.Println(errors.Wrap(err, "error creating error writer"))
+		fmt.Println(err.Error())
 		os.Exit(1)
 	}
+	return logger
+}
 
-	levels := map[string]int{"error": 0, "fatal": 0}
-	writers := []Writer{errorWriter}
-	formats := []string{input.ErrorFormat}
-
-	if input.Verbose {
-		levels["warn"] = 0
-	}
-
-	if len(input.InfoDestination) > 0 && input.InfoDestination != "/dev/null" && input.InfoDestination != "null" {
-		if input.InfoDestination == input.ErrorDestination {
-			if input.InfoFormat != input.ErrorFormat {
-				_, err := errorWriter.WriteError(fmt.Errorf("info-format ( %s ) and error-format ( %s ) must match when they share a destination", input.InfoFormat, input.ErrorFormat)) // #nosec
-				if err != nil {
-					fmt.Println(err.Error())
-				}
-				errorWriter.Close() // #nosec
-				os.Exit(1)
-			}
-			if input.InfoCompression != input.ErrorCompression {
-				_, err := errorWriter.WriteError(fmt.Errorf("info-compression ( %s ) and error-compression ( %s ) must match when they share a destination", input.InfoCompression, input.ErrorCompression)) // #nosec
-				if err != nil {
-					fmt.Println(err.Error())
-				}
-				errorWriter.Close() // #nosec
-				os.Exit(1)
-			}
-
-			levels["info"] = 0
-			if input.Verbose {
-				levels["debug"] = 0
+// Validate returns an ErrInvalidConfig error if a format or compression algorithm is not supported, otherwise returns nil.
+// The path of the error is the name of the matching flag, e.g., "error-format", or the position in destinations, e.g., "destinations[1].format".
+func (input *CreateApplicationLoggerInput) Validate() error {
+	if len(input.Destinations) > 0 {
+		for i, d := range input.Destinations {
+			if _, err := ParseLevel(d.Level); err != nil {
+				return &ErrInvalidConfig{Path: fmt.Sprintf("destinations[%d].level", i), Err: err}
 			}
-		} else {
-			infoWriter, err := grw.WriteToResource(&grw.WriteToResourceInput{
-				Uri:      input.InfoDestination,
-				Alg:      input.InfoCompression,
-				Dict:     grw.NoDict,
-				Append:   true,
-				S3Client: nil,
-			})
-			if err != nil {
-				errorWriter.WriteError(errors.Wrap(err, "error creating log writer")) // #nosec
-				errorWriter.Close()                                                   // #nosec
-				os.Exit(1)
+			if err := ValidateFormat(d.Format); err != nil {
+				return &ErrInvalidConfig{Path: fmt.Sprintf("destinations[%d].format", i), Err: err}
 			}
-
-			levels["info"] = 1
-			writers = append(writers, infoWriter)
-			formats = append(formats, input.InfoFormat)
-
-			if input.Verbose {
-				levels["debug"] = 1
+			if err := ValidateCompression(d.Compression); err != nil {
+				return &ErrInvalidConfig{Path: fmt.Sprintf("destinations[%d].compression", i), Err: err}
 			}
 		}
+		return nil
 	}
-
-	logger := NewLogger(levels, writers, formats, true)
-	return logger
+	if err := ValidateFormat(input.ErrorFormat); err != nil {
+		return &ErrInvalidConfig{Path: FlagErrorFormat, Err: err}
+	}
+	if err := ValidateCompression(input.ErrorCompression); err != nil {
+		return &ErrInvalidConfig{Path: FlagErrorCompression, Err: err}
+	}
+	if !isNullDestination(input.InfoDestination) {
+		if err := ValidateFormat(input.InfoFormat); err != nil {
+			return &ErrInvalidConfig{Path: FlagInfoFormat, Err: err}
+		}
+		if err := ValidateCompression(input.InfoCompression); err != nil {
+			return &ErrInvalidConfig{Path: FlagInfoCompression, Err: err}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,25 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrCompressionMismatch is returned when two levels share a destination, but use different compression values.
+type ErrCompressionMismatch struct {
+	Destination      string // the shared destination
+	Level            string // the level whose compression conflicts
+	Compression      string // the compression for the level that conflicts
+	OtherLevel       string // the level that first configured the destination
+	OtherCompression string // the compression for the level that first configured the destination
+}
+
+func (e *ErrCompressionMismatch) Error() string {
+	return fmt.Sprintf("%s-compression ( %s ) and %s-compression ( %s ) must match when they share a destination ( %s )", e.Level, e.Compression, e.OtherLevel, e.OtherCompression, e.Destination)
+}
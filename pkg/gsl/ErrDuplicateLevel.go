@@ -0,0 +1,21 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrDuplicateLevel is returned when a level is configured more than once.
+type ErrDuplicateLevel struct {
+	Level string
+}
+
+func (e *ErrDuplicateLevel) Error() string {
+	return fmt.Sprintf("level %s is configured more than once", e.Level)
+}
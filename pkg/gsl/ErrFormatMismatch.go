@@ -0,0 +1,25 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrFormatMismatch is returned when two levels share a destination, but use different format values.
+type ErrFormatMismatch struct {
+	Destination string // the shared destination
+	Level       string // the level whose format conflicts
+	Format      string // the format for the level that conflicts
+	OtherLevel  string // the level that first configured the destination
+	OtherFormat string // the format for the level that first configured the destination
+}
+
+func (e *ErrFormatMismatch) Error() string {
+	return fmt.Sprintf("%s-format ( %s ) and %s-format ( %s ) must match when they share a destination ( %s )", e.Level, e.Format, e.OtherLevel, e.OtherFormat, e.Destination)
+}
@@ -0,0 +1,33 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrInvalidConfig is returned when a logger configuration is invalid.
+// The path points to the offending value in the configuration, e.g., "writers[1].format".
+type ErrInvalidConfig struct {
+	Path string // the path to the invalid value
+	Err  error  // the underlying error
+}
+
+func (e *ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("invalid config at %s: %s", e.Path, e.Err.Error())
+}
+
+// Cause returns the underlying error for compatibility with github.com/pkg/errors.
+func (e *ErrInvalidConfig) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error for compatibility with the errors package in the standard library.
+func (e *ErrInvalidConfig) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,22 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrInvalidPosition is returned when a level is routed to a writer position that does not exist.
+type ErrInvalidPosition struct {
+	Position int // the requested position
+	Writers  int // the number of writers
+}
+
+func (e *ErrInvalidPosition) Error() string {
+	return fmt.Sprintf("invalid writer position %d, expecting a position between 0 and %d", e.Position, e.Writers-1)
+}
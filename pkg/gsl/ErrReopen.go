@@ -0,0 +1,26 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"strings"
+)
+
+// ErrReopen is returned by Reopen when one or more writers cannot be reopened.
+// The writers that could not be reopened are left open and still used.
+type ErrReopen struct {
+	Errors []error // the error for each writer that could not be reopened
+}
+
+func (e *ErrReopen) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return "error reopening writers: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,22 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownCompression is returned when a compression algorithm is not supported.
+type ErrUnknownCompression struct {
+	Compression string
+}
+
+func (e *ErrUnknownCompression) Error() string {
+	return fmt.Sprintf("unknown compression %q, expecting one of %s", e.Compression, strings.Join(Algorithms[1:], ", "))
+}
@@ -0,0 +1,22 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownFormat is returned when a format is not supported.
+type ErrUnknownFormat struct {
+	Format string
+}
+
+func (e *ErrUnknownFormat) Error() string {
+	return fmt.Sprintf("unknown format %q, expecting one of %s", e.Format, strings.Join(Formats, ", "))
+}
@@ -0,0 +1,32 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// ErrWriterCreate is returned when a writer cannot be created for a destination.
+type ErrWriterCreate struct {
+	Destination string // the destination of the writer
+	Err         error  // the underlying error
+}
+
+func (e *ErrWriterCreate) Error() string {
+	return fmt.Sprintf("error creating writer for %s: %s", e.Destination, e.Err.Error())
+}
+
+// Cause returns the underlying error for compatibility with github.com/pkg/errors.
+func (e *ErrWriterCreate) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error for compatibility with the errors package in the standard library.
+func (e *ErrWriterCreate) Unwrap() error {
+	return e.Err
+}
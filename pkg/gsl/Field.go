@@ -0,0 +1,111 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"math"
+	"time"
+)
+
+// FieldType is the type of the value stored in a Field.
+type FieldType uint8
+
+const (
+	SkipType     FieldType = iota // field is not written
+	StringType                    // value is stored in String
+	Int64Type                     // value is stored in Integer
+	Float64Type                   // value is stored in Integer as the IEEE 754 bits
+	BoolType                      // value is stored in Integer as 0 or 1
+	DurationType                  // value is stored in Integer as nanoseconds
+	TimeType                      // value is stored in Interface
+	AnyType                       // value is stored in Interface
+)
+
+// Field is a typed key-value pair written using Logger.Log.
+// Storing common types without boxing them in an interface{} avoids allocations on hot paths.
+// A field serializes identically to the same key and value in a map[string]interface{}.
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	String    string
+	Interface interface{}
+}
+
+// String returns a field with a string value.
+func String(key string, value string) Field {
+	return Field{Key: key, Type: StringType, String: value}
+}
+
+// Int returns a field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: Int64Type, Integer: int64(value)}
+}
+
+// Int64 returns a field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: Int64Type, Integer: value}
+}
+
+// Float64 returns a field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: Float64Type, Integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a field with a bool value.
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: BoolType, Integer: i}
+}
+
+// Duration returns a field with a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Integer: int64(value)}
+}
+
+// Time returns a field with a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: TimeType, Interface: value}
+}
+
+// Err returns a field with the key "error" and the message of the error as the value.
+// If the error is nil, then the field is not written.
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Type: SkipType}
+	}
+	return Field{Key: "error", Type: StringType, String: err.Error()}
+}
+
+// Any returns a field with an arbitrary value.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: AnyType, Interface: value}
+}
+
+// Value returns the value of the field as it would be stored in a map[string]interface{}.
+// Value boxes the value, so the logger only calls it when a message is formatted through a map.
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.String
+	case Int64Type:
+		return f.Integer
+	case Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	case TimeType, AnyType:
+		return f.Interface
+	}
+	return nil
+}
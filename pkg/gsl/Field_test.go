@@ -0,0 +1,76 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+func TestLoggerLog(t *testing.T) {
+
+	now := time.Now()
+
+	for _, format := range Formats {
+
+		fieldsWriter, fieldsBytes := grw.WriteMemoryBytes()
+		mapWriter, mapBytes := grw.WriteMemoryBytes()
+
+		levels := map[string]int{"info": 0}
+		autoFlush := true
+
+		// use a timestamp that does not change during the test
+		fieldsLogger := NewLogger(levels, []Writer{fieldsWriter}, []string{format}, autoFlush).With(map[string]interface{}{"service": "api"})
+		fieldsLogger.TimeStampFormat = "2006"
+
+		mapLogger := NewLogger(levels, []Writer{mapWriter}, []string{format}, autoFlush).With(map[string]interface{}{"service": "api"})
+		mapLogger.TimeStampFormat = "2006"
+
+		err := fieldsLogger.Log(
+			LevelInfo,
+			testMessage,
+			String("s", "x"),
+			String("html", "<a & b>\n"),
+			Int("i", 1),
+			Int64("i64", 2),
+			Float64("f", 1.5),
+			Float64("small", 1e-7),
+			Bool("b", true),
+			Duration("d", time.Second),
+			Time("t", now),
+			Err(fmt.Errorf("failed")),
+			Err(nil),
+			Any("a", []string{"y", "z"}),
+		)
+		assert.NoError(t, err)
+
+		err = mapLogger.Info(map[string]interface{}{
+			"msg":   testMessage,
+			"s":     "x",
+			"html":  "<a & b>\n",
+			"i":     1,
+			"i64":   int64(2),
+			"f":     1.5,
+			"small": 1e-7,
+			"b":     true,
+			"d":     time.Second,
+			"t":     now,
+			"error": "failed",
+			"a":     []string{"y", "z"},
+		})
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, fieldsBytes.Bytes())
+		assert.Equal(t, mapBytes.String(), fieldsBytes.String(), format)
+	}
+}
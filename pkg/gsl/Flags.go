@@ -0,0 +1,129 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagErrorDestination = "error-destination" // flag for the destination of error messages
+	FlagErrorCompression = "error-compression" // flag for the compression of error messages
+	FlagErrorFormat      = "error-format"      // flag for the format of error messages
+	FlagInfoDestination  = "info-destination"  // flag for the destination of info messages
+	FlagInfoCompression  = "info-compression"  // flag for the compression of info messages
+	FlagInfoFormat       = "info-format"       // flag for the format of info messages
+	FlagVerbose          = "verbose"           // flag for verbose mode
+)
+
+const (
+	DefaultErrorDestination = "stderr" // default destination of error messages
+	DefaultErrorCompression = ""       // default compression of error messages
+	DefaultErrorFormat      = "json"   // default format of error messages
+	DefaultInfoDestination  = "stdout" // default destination of info messages
+	DefaultInfoCompression  = ""       // default compression of info messages
+	DefaultInfoFormat       = "json"   // default format of info messages
+)
+
+// RegisterFlags registers the flags used to create an application logger with the given flag set.
+// Use InputFromFlags to read the values after the flags are parsed.
+func RegisterFlags(flag *pflag.FlagSet) {
+	formats := strings.Join(Formats, ", ")
+	algorithms := strings.Join(Algorithms[1:], ", ")
+	flag.String(FlagErrorDestination, DefaultErrorDestination, "destination for error messages, e.g., stderr or errors.log")
+	flag.String(FlagErrorCompression, DefaultErrorCompression, "compression for error messages: "+algorithms)
+	flag.String(FlagErrorFormat, DefaultErrorFormat, "format for error messages: "+formats)
+	flag.String(FlagInfoDestination, DefaultInfoDestination, "destination for info messages, e.g., stdout or info.log")
+	flag.String(FlagInfoCompression, DefaultInfoCompression, "compression for info messages: "+algorithms)
+	flag.String(FlagInfoFormat, DefaultInfoFormat, "format for info messages: "+formats)
+	flag.Bool(FlagVerbose, false, "verbose mode, which writes warn and debug messages")
+}
+
+// InputFromFlags returns the input for CreateApplicationLogger from the flags registered by RegisterFlags.
+// If a value is invalid, then returns an ErrInvalidConfig error with the name of the flag as the path.
+func InputFromFlags(flag *pflag.FlagSet) (*CreateApplicationLoggerInput, error) {
+	values := map[string]string{}
+	for _, name := range []string{FlagErrorDestination, FlagErrorCompression, FlagErrorFormat, FlagInfoDestination, FlagInfoCompression, FlagInfoFormat} {
+		value, err := flag.GetString(name)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error getting value for flag %s", name))
+		}
+		values[name] = value
+	}
+	verbose, err := flag.GetBool(FlagVerbose)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error getting value for flag %s", FlagVerbose))
+	}
+	input := &CreateApplicationLoggerInput{
+		ErrorDestination: values[FlagErrorDestination],
+		ErrorCompression: values[FlagErrorCompression],
+		ErrorFormat:      values[FlagErrorFormat],
+		InfoDestination:  values[FlagInfoDestination],
+		InfoCompression:  values[FlagInfoCompression],
+		InfoFormat:       values[FlagInfoFormat],
+		Verbose:          verbose,
+	}
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// FromEnv returns the input for CreateApplicationLogger from environment variables with the given prefix.
+// The names of the environment variables are the names of the flags in upper case with underscores, e.g., the prefix "APP" and flag "error-destination" become "APP_ERROR_DESTINATION".
+// Variables that are not set use the same defaults as the flags.
+// If a value is invalid, then returns an ErrInvalidConfig error with the name of the environment variable as the path.
+func FromEnv(prefix string) (*CreateApplicationLoggerInput, error) {
+	input := &CreateApplicationLoggerInput{
+		ErrorDestination: lookupEnv(prefix, FlagErrorDestination, DefaultErrorDestination),
+		ErrorCompression: lookupEnv(prefix, FlagErrorCompression, DefaultErrorCompression),
+		ErrorFormat:      lookupEnv(prefix, FlagErrorFormat, DefaultErrorFormat),
+		InfoDestination:  lookupEnv(prefix, FlagInfoDestination, DefaultInfoDestination),
+		InfoCompression:  lookupEnv(prefix, FlagInfoCompression, DefaultInfoCompression),
+		InfoFormat:       lookupEnv(prefix, FlagInfoFormat, DefaultInfoFormat),
+	}
+	if str := lookupEnv(prefix, FlagVerbose, ""); len(str) > 0 {
+		verbose, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, &ErrInvalidConfig{Path: envName(prefix, FlagVerbose), Err: err}
+		}
+		input.Verbose = verbose
+	}
+	err := input.Validate()
+	if err != nil {
+		if e, ok := err.(*ErrInvalidConfig); ok {
+			e.Path = envName(prefix, e.Path)
+		}
+		return nil, err
+	}
+	return input, nil
+}
+
+// envName returns the name of the environment variable for the given prefix and flag.
+func envName(prefix string, flag string) string {
+	name := strings.ToUpper(strings.Replace(flag, "-", "_", -1))
+	if len(prefix) > 0 {
+		return strings.ToUpper(prefix) + "_" + name
+	}
+	return name
+}
+
+// lookupEnv returns the value of the environment variable for the given prefix and flag, or the default value if not set.
+func lookupEnv(prefix string, flag string, defaultValue string) string {
+	if value, ok := os.LookupEnv(envName(prefix, flag)); ok {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,65 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputFromFlags(t *testing.T) {
+	flag := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags(flag)
+
+	err := flag.Parse([]string{"--info-destination", "info.log", "--info-compression", "gzip", "--verbose"})
+	assert.NoError(t, err)
+
+	input, err := InputFromFlags(flag)
+	assert.NoError(t, err)
+	assert.Equal(t, &CreateApplicationLoggerInput{
+		ErrorDestination: DefaultErrorDestination,
+		ErrorCompression: DefaultErrorCompression,
+		ErrorFormat:      DefaultErrorFormat,
+		InfoDestination:  "info.log",
+		InfoCompression:  "gzip",
+		InfoFormat:       DefaultInfoFormat,
+		Verbose:          true,
+	}, input)
+
+	err = flag.Parse([]string{"--error-format", "xml"})
+	assert.NoError(t, err)
+
+	_, err = InputFromFlags(flag)
+	if assert.IsType(t, &ErrInvalidConfig{}, err) {
+		assert.Equal(t, FlagErrorFormat, err.(*ErrInvalidConfig).Path)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer os.Unsetenv("TEST_INFO_FORMAT") // #nosec
+	defer os.Unsetenv("TEST_VERBOSE")     // #nosec
+
+	os.Setenv("TEST_INFO_FORMAT", "tags") // #nosec
+	os.Setenv("TEST_VERBOSE", "true")     // #nosec
+
+	input, err := FromEnv("test")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultErrorDestination, input.ErrorDestination)
+	assert.Equal(t, "tags", input.InfoFormat)
+	assert.True(t, input.Verbose)
+
+	os.Setenv("TEST_INFO_FORMAT", "xml") // #nosec
+
+	_, err = FromEnv("test")
+	if assert.IsType(t, &ErrInvalidConfig{}, err) {
+		assert.Equal(t, "TEST_INFO_FORMAT", err.(*ErrInvalidConfig).Path)
+	}
+}
@@ -0,0 +1,55 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// Formats is the list of formats supported for writing messages.
+// The formats are implemented by go-simple-serializer.
+//  - https://github.com/spatialcurrent/go-simple-serializer
+var Formats = []string{
+	"csv",
+	"json",
+	"jsonl",
+	"properties",
+	"tags",
+	"toml",
+	"tsv",
+	"yaml",
+}
+
+// Algorithms is the list of compression algorithms supported for writing messages.
+// An empty string or "none" means no compression.
+// The algorithms are implemented by go-reader-writer.
+//  - https://github.com/spatialcurrent/go-reader-writer
+var Algorithms = []string{
+	"",
+	"none",
+	"flate",
+	"gzip",
+	"snappy",
+	"zlib",
+}
+
+// ValidateFormat returns an ErrUnknownFormat error if the format is not supported.
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return &ErrUnknownFormat{Format: format}
+}
+
+// ValidateCompression returns an ErrUnknownCompression error if the compression algorithm is not supported.
+func ValidateCompression(compression string) error {
+	for _, alg := range Algorithms {
+		if compression == alg {
+			return nil
+		}
+	}
+	return &ErrUnknownCompression{Compression: compression}
+}
@@ -0,0 +1,59 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+//go:build !windows
+// +build !windows
+
+package gsl
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleSignals starts a goroutine that toggles verbose mode on SIGUSR1 and reopens file-backed writers on SIGHUP.
+// Reopening on SIGHUP allows log rotation tools, such as logrotate, to move the log files without losing messages.
+// Errors reopening the writers are written to the error writer.
+// Call the returned function to stop handling the signals.
+func (l *Logger) HandleSignals() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case s := <-signals:
+				l.handleSignal(s)
+			case <-done:
+				return
+			}
+		}
+	}()
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// handleSignal toggles verbose mode on SIGUSR1 and reopens file-backed writers on SIGHUP.
+// Other signals are ignored.
+func (l *Logger) handleSignal(s os.Signal) {
+	switch s {
+	case syscall.SIGUSR1:
+		l.SetVerbose(!l.Verbose())
+	case syscall.SIGHUP:
+		err := l.Reopen()
+		if err != nil {
+			l.Error(err) // #nosec
+		}
+	}
+}
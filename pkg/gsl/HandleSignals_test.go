@@ -0,0 +1,111 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+//go:build !windows
+// +build !windows
+
+package gsl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerHandleSignals(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l := CreateApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: path,
+		ErrorFormat:      "json",
+		InfoDestination:  path,
+		InfoFormat:       "json",
+	})
+	defer l.Close()
+
+	stop := l.HandleSignals()
+	stop()
+	stop()
+
+	assert.False(t, l.Verbose())
+	assert.False(t, l.Enabled(LevelDebug))
+
+	l.handleSignal(syscall.SIGUSR1)
+
+	assert.True(t, l.Verbose())
+	assert.True(t, l.Enabled(LevelDebug))
+	assert.True(t, l.Enabled(LevelWarn))
+
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+
+	err = os.Rename(path, path+".1")
+	assert.NoError(t, err)
+
+	l.handleSignal(syscall.SIGHUP)
+
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	rotated, err := ioutil.ReadFile(path + ".1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rotated)
+
+	current, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, current)
+}
+
+func TestLoggerReopenError(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l := CreateApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: path,
+		ErrorFormat:      "json",
+		InfoDestination:  path,
+		InfoFormat:       "json",
+	})
+	defer l.Close()
+
+	err = os.Rename(path, path+".1")
+	assert.NoError(t, err)
+
+	// a directory at the path cannot be opened for writing
+	err = os.Mkdir(path, 0700)
+	assert.NoError(t, err)
+
+	err = l.Reopen()
+	assert.IsType(t, &ErrReopen{}, err)
+
+	// the existing writer is still open and used
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	rotated, err := ioutil.ReadFile(path + ".1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rotated)
+}
@@ -0,0 +1,15 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// HandleSignals is a no-op on Windows, since SIGUSR1 and SIGHUP are not supported.
+// Use SetVerbose and Reopen directly instead.
+// Call the returned function to stop handling the signals.
+func (l *Logger) HandleSignals() func() {
+	return func() {}
+}
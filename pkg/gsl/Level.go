@@ -0,0 +1,62 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"strings"
+)
+
+// Level is the severity of a message.
+// Levels are ordered from least to most severe, so a level can be compared to a minimum threshold.
+type Level int
+
+const (
+	LevelTrace Level = iota // trace level
+	LevelDebug              // debug level
+	LevelInfo               // info level
+	LevelWarn               // warn level
+	LevelError              // error level
+	LevelFatal              // fatal level
+)
+
+// Levels is the list of all the levels in order of severity.
+var Levels = []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal}
+
+var levelNames = map[Level]string{
+	LevelTrace: "trace",
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelFatal: "fatal",
+}
+
+// String returns the name of the level, e.g., "info".
+// The name is used as the key in the levels map passed to NewLogger.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLevel parses a level from its name.
+// Parsing is case-insensitive and "warning" is accepted as an alias for "warn".
+// If the name does not match any level, then returns an ErrUnknownLevel error.
+func ParseLevel(str string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(str))
+	if name == "warning" {
+		return LevelWarn, nil
+	}
+	for level, levelName := range levelNames {
+		if name == levelName {
+			return level, nil
+		}
+	}
+	return LevelTrace, &ErrUnknownLevel{Level: str}
+}
@@ -0,0 +1,17 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// LevelDestination describes where and how the messages for a level are written.
+// Levels with the same destination share a single writer, so they must use the same compression and format.
+type LevelDestination struct {
+	Level       string // the level, e.g., "debug"
+	Destination string // the uri of the destination, e.g., "stderr" or "debug.log.gz"
+	Compression string // the compression algorithm, e.g., "gzip"
+	Format      string // the format of the messages, e.g., "json"
+}
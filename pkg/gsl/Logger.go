@@ -8,173 +8,587 @@
 package gsl
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
 	"github.com/spatialcurrent/go-simple-serializer/pkg/gss"
 )
 
 // Logger contains a slice of writers, a slice of matching formats, and a mapping of levels to writers.
 type Logger struct {
-	levels          map[string]int // level --> position in writers
-	writers         []Writer       // list of writers
-	formats         []string       // list of formats for each writer
-	LevelField      string         // the key for the level field
-	TimeStampField  string         // the key for the timestamp field
-	TimeStampFormat string         // the format for the timestamp field
-	MessageField    string         // the key for the message field
-	AutoFlush       bool           // flush after every message
+	router            *router                // routing of levels to writers shared with child loggers
+	LevelField        string                 // the key for the level field
+	TimeStampField    string                 // the key for the timestamp field
+	TimeStampFormat   string                 // the format for the timestamp field
+	MessageField      string                 // the key for the message field
+	ObjectField       string                 // the key for objects that are not strings, errors, maps, or structs
+	ErrorField        string                 // the prefix for the error type, chain, and stack fields, empty to disable
+	ErrorChainDepth   int                    // the maximum number of errors in the chain, zero for no limit
+	ErrorStackDepth   int                    // the maximum number of frames in the stack, zero for no limit
+	ErrorStackFormats []string               // the formats that include the stack trace
+	AddCaller         bool                   // add the file and line of the caller to every message
+	AddFunc           bool                   // add the function of the caller to every message, if AddCaller is true
+	CallerSkip        int                    // the number of additional frames to skip when finding the caller
+	CallerField       string                 // the key for the caller field
+	FuncField         string                 // the key for the function field
+	FullCallerPath    bool                   // use the full path of the file rather than the parent directory and file name
+	AutoFlush         bool                   // flush after every message
+	Collision         CollisionPolicy        // what happens when an object contains the level or timestamp key
+	FieldsKey         string                 // the key used by the collision policy, defaults to "fields"
+	ExitFunc          func(code int)         // called by Fatal to exit, defaults to os.Exit
+	ExitCode          int                    // the exit code used by Fatal, defaults to 1
+	fields            map[string]interface{} // fields bound to every message
+	caller            *runtime.Frame         // caller pinned by a Listen method
+	timeStamp         time.Time              // timestamp pinned by the slog handler, zero for the current time
+	hooks             *shutdownHooks         // shutdown hooks shared with child loggers
+	limits            *limitRules            // rules used by Once, Every, and Throttle shared with child loggers
+	limit             *limitRule             // rule for the messages written by this view
 }
 
 // NewLogger returns a new logger with the given configuration and default field keys.
 // Set autoFlush to true to flush the buffer to the underlying writer after every message.
+// The levels map is copied, so later changes to the map do not affect the logger.
 func NewLogger(levels map[string]int, writers []Writer, formats []string, autoFlush bool) *Logger {
 	return &Logger{
-		levels:          levels,
-		writers:         writers,
-		formats:         formats,
-		TimeStampField:  "ts",
-		TimeStampFormat: time.RFC3339,
-		LevelField:      "level",
-		MessageField:    "msg",
-		AutoFlush:       autoFlush,
+		router:            newRouter(levels, writers, formats),
+		TimeStampField:    "ts",
+		TimeStampFormat:   time.RFC3339,
+		LevelField:        "level",
+		MessageField:      "msg",
+		ObjectField:       "obj",
+		ErrorField:        "error",
+		ErrorStackDepth:   32,
+		ErrorStackFormats: []string{"json", "jsonl"},
+		CallerField:       "caller",
+		FuncField:         "func",
+		AutoFlush:         autoFlush,
+		Collision:         CollisionOverwrite,
+		FieldsKey:         "fields",
+		ExitFunc:          os.Exit,
+		ExitCode:          1,
+		hooks:             &shutdownHooks{},
+		limits:            &limitRules{},
 	}
 }
 
-// Debug writes the provided object to the `debug` writer.
-// If no `debug` writer exists, then return an ErrUnknownLevel error.
-func (l *Logger) Debug(obj interface{}) error {
-	level := "debug"
-	position, ok := l.levels[level]
+// With returns a child logger that shares the writers, formats, and levels of the parent logger,
+// but includes the given fields in every message along with any fields already bound to the parent.
+// If a field is bound to both the parent and the child, the child's value is used.
+// The level, timestamp, and message fields always take precedence over bound fields.
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	child := *l
+	child.fields = make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		child.fields[k] = v
+	}
+	for k, v := range fields {
+		child.fields[k] = v
+	}
+	return &child
+}
+
+// Once returns a view of the logger that only writes the first message written through any view with the same key,
+// e.g., to warn about a deprecated setting once per process.
+// The rule is kept for the life of the logger, so keys should be from a bounded set, e.g., constants.
+// Messages dropped because their level is below the minimum level or not routed do not count.
+// Fatal always writes the message and exits.
+func (l *Logger) Once(key string) *Logger {
+	return l.withLimit(l.limits.get("once", key, 0, 0))
+}
+
+// Every returns a view of the logger that writes the first message and then every nth message written through any view with the same key.
+// If n is less than one, then every message is written.
+// Calls with the same key and a different n use separate rules, e.g., Every(2, key) and Every(3, key) count separately.
+// The rule is kept for the life of the logger, so keys should be from a bounded set, e.g., constants.
+// Messages dropped because their level is below the minimum level or not routed do not count.
+// Fatal always writes the message and exits.
+func (l *Logger) Every(n int, key string) *Logger {
+	if n < 1 {
+		n = 1
+	}
+	return l.withLimit(l.limits.get("every", key, n, 0))
+}
+
+// Throttle returns a view of the logger that writes at most one message per interval through any view with the same key,
+// e.g., to warn in a retry loop at most every 30 seconds.
+// Calls with the same key and a different interval use separate rules.
+// Rules whose interval has passed are evicted, so the number of rules is bounded by the keys used within an interval.
+// Messages dropped because their level is below the minimum level or not routed do not count.
+// Fatal always writes the message and exits.
+func (l *Logger) Throttle(key string, interval time.Duration) *Logger {
+	return l.withLimit(l.limits.get("throttle", key, 0, interval))
+}
+
+// withLimit returns a copy of the logger that uses the given rule.
+func (l *Logger) withLimit(rule *limitRule) *Logger {
+	view := *l
+	view.limit = rule
+	return &view
+}
+
+// SetMinLevel sets the minimum level for the logger.
+// Messages below the minimum level are dropped silently.
+// The minimum level is shared with child loggers.
+// SetMinLevel is safe to call while other goroutines are writing messages.
+func (l *Logger) SetMinLevel(level Level) {
+	l.router.Lock()
+	l.router.minLevel = level
+	l.router.Unlock()
+}
+
+// MinLevel returns the minimum level for the logger.
+func (l *Logger) MinLevel() Level {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	return l.router.minLevel
+}
+
+// SetLevel routes the given level to the writer at the given position.
+// If the position does not exist, then returns an ErrInvalidPosition error.
+// The routing is shared with child loggers.
+// SetLevel is safe to call while other goroutines are writing messages.
+func (l *Logger) SetLevel(level Level, position int) error {
+	l.router.Lock()
+	defer l.router.Unlock()
+	if position < 0 || position >= len(l.router.writers) {
+		return &ErrInvalidPosition{Position: position, Writers: len(l.router.writers)}
+	}
+	l.router.levels[level.String()] = position
+	delete(l.router.disabled, level.String())
+	return nil
+}
+
+// EnableLevel routes a level previously disabled with DisableLevel to the writer it was routed to before.
+// If the level is already enabled, then returns nil.
+// If the level was never routed to a writer, then returns an ErrUnknownLevel error.
+// If the writer no longer exists, e.g., after ApplyConfig removed writers, then returns an ErrInvalidPosition error.
+func (l *Logger) EnableLevel(level Level) error {
+	l.router.Lock()
+	defer l.router.Unlock()
+	name := level.String()
+	if _, ok := l.router.levels[name]; ok {
+		return nil
+	}
+	position, ok := l.router.disabled[name]
 	if !ok {
-		return &ErrUnknownLevel{Level: level}
+		return &ErrUnknownLevel{Level: name}
 	}
-	_, err := l.WriteLineSafe(level, obj, l.writers[position], l.formats[position])
-	if err != nil {
-		return errors.Wrap(err, "error writing debug message")
+	if position >= len(l.router.writers) {
+		return &ErrInvalidPosition{Position: position, Writers: len(l.router.writers)}
 	}
+	l.router.levels[name] = position
+	delete(l.router.disabled, name)
 	return nil
 }
 
-// DebugF writes the provided message to the `debug` writer.
-// The message is generated using `fmt.Sprintf(format, values...)`.
-// If no `debug` writer exists, then return an ErrUnknownLevel error.
-func (l *Logger) DebugF(format string, values ...interface{}) error {
-	level := "debug"
-	position, ok := l.levels[level]
+// DisableLevel stops routing the given level to a writer.
+// Messages at a disabled level return an ErrUnknownLevel error, unless they are below the minimum level.
+// The previous position is remembered, so the level can be re-enabled with EnableLevel.
+func (l *Logger) DisableLevel(level Level) {
+	l.router.Lock()
+	defer l.router.Unlock()
+	name := level.String()
+	if position, ok := l.router.levels[name]; ok {
+		l.router.disabled[name] = position
+		delete(l.router.levels, name)
+	}
+}
+
+// Levels returns a copy of the current mapping of levels to writer positions.
+func (l *Logger) Levels() map[string]int {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	levels := make(map[string]int, len(l.router.levels))
+	for level, position := range l.router.levels {
+		levels[level] = position
+	}
+	return levels
+}
+
+// Formats returns a copy of the formats for each writer.
+func (l *Logger) Formats() []string {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	formats := make([]string, len(l.router.formats))
+	copy(formats, l.router.formats)
+	return formats
+}
+
+// SetVerbose turns verbose mode on or off.
+// When verbose mode is on, the verbose levels are routed to their writers and the minimum level is lowered to debug, if higher.
+// When verbose mode is off, the verbose levels are not routed and the minimum level is raised to info, if lower.
+// For loggers created by CreateApplicationLogger, the verbose levels are warn and debug.
+func (l *Logger) SetVerbose(verbose bool) {
+	l.router.Lock()
+	defer l.router.Unlock()
+	for level, position := range l.router.verboseLevels {
+		if verbose {
+			l.router.levels[level] = position
+		} else {
+			delete(l.router.levels, level)
+		}
+	}
+	if verbose && l.router.minLevel > LevelDebug {
+		l.router.minLevel = LevelDebug
+	} else if !verbose && l.router.minLevel < LevelInfo {
+		l.router.minLevel = LevelInfo
+	}
+	l.router.verbose = verbose
+}
+
+// Verbose returns true if verbose mode is on.
+func (l *Logger) Verbose() bool {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	return l.router.verbose
+}
+
+// Reopen reopens the writers that were created from files, e.g., by CreateApplicationLogger.
+// The files are reopened in append mode using their original uri, so log rotation by moving the file works without losing messages.
+// Writers for stdout, stderr, and other resources are not changed.
+// Each new writer is opened before the existing writer is flushed and closed, so if a file cannot be reopened, then the existing writer is still used.
+// Reopen continues with the remaining files and returns an ErrReopen error with the errors for the files that could not be reopened.
+// Reopen waits for messages that are being written to complete before closing the writers.
+func (l *Logger) Reopen() error {
+	l.router.RLock()
+	destinations := make([]destination, len(l.router.destinations))
+	copy(destinations, l.router.destinations)
+	l.router.RUnlock()
+
+	errs := make([]error, 0)
+	opened := make(map[int]Writer, len(destinations))
+	for i, d := range destinations {
+		if !isFileURI(d.uri) {
+			continue
+		}
+		nw, err := grw.WriteToResource(&grw.WriteToResourceInput{
+			Uri:      d.uri,
+			Alg:      d.compression,
+			Dict:     grw.NoDict,
+			Append:   true,
+			S3Client: nil,
+		})
+		if err != nil {
+			errs = append(errs, &ErrWriterCreate{Destination: d.uri, Err: err})
+			continue
+		}
+		opened[i] = nw
+	}
+
+	l.router.Lock()
+	for i, nw := range opened {
+		// the writers may have been replaced by ApplyConfig while the files were opened
+		if i >= len(l.router.destinations) || l.router.destinations[i] != destinations[i] {
+			nw.Close() // #nosec
+			continue
+		}
+		w := l.router.writers[i]
+		w.Lock()
+		w.Flush() // #nosec
+		w.Close() // #nosec
+		w.Unlock()
+		l.router.writers[i] = l.router.wrap(nw)
+	}
+	l.router.Unlock()
+
+	if len(errs) > 0 {
+		return &ErrReopen{Errors: errs}
+	}
+	return nil
+}
+
+// Enabled returns true if a message at the given level would be written by the logger.
+func (l *Logger) Enabled(level Level) bool {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	if level < l.router.minLevel {
+		return false
+	}
+	_, ok := l.router.levels[level.String()]
+	return ok
+}
+
+// write writes the provided object to the writer for the given level.
+// If the level is below the minimum level or the message is sampled out, then the message is dropped and returns nil.
+// If no writer exists for the level, then return an ErrUnknownLevel error.
+func (l *Logger) write(level Level, obj interface{}) error {
+	return l.output(level, obj, true)
+}
+
+// output writes the object to the writer for the given level.
+// If filter is true, then the object may be sampled out, limited by a view, or suppressed as a duplicate.
+// The messages written by the logger itself, such as summaries, are not filtered.
+func (l *Logger) output(level Level, obj interface{}, filter bool) error {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	if level < l.router.minLevel {
+		return nil
+	}
+	name := level.String()
+	if _, ok := l.router.levels[name]; !ok {
+		return &ErrUnknownLevel{Level: name}
+	}
+	if filter {
+		if s := l.router.sampler; s != nil && !s.allow(level, l.messageOf(obj)) {
+			return nil
+		}
+		if l.limit != nil && !l.limits.allow(l.limit) {
+			return nil
+		}
+	}
+	// the caller is only added to messages that are written, since finding the caller is expensive
+	if l.AddCaller {
+		l = l.withCaller()
+	}
+	d := l.router.deduper
+	if !filter || d == nil {
+		return l.emit(level, obj)
+	}
+	// the timestamp is pinned, so it can be removed from the formatted line when computing the signature
+	now := time.Now()
+	if l.timeStamp.IsZero() {
+		pinned := *l
+		pinned.timeStamp = now
+		l = &pinned
+	}
+	position, line, err := l.format(level, obj)
+	if err != nil {
+		return err
+	}
+	ok, repeated := d.check(level, l.signature(line), now)
+	if repeated != nil {
+		d.logger.emit(repeated.level, d.summary(repeated)) // #nosec
+	}
 	if !ok {
-		return &ErrUnknownLevel{Level: level}
+		return nil
 	}
-	_, err := l.WriteLineSafe(level, fmt.Sprintf(format, values...), l.writers[position], l.formats[position])
+	return l.writeFormatted(level, position, line)
+}
+
+// emit formats the object and writes it to the writer for the given level, or queues it in async mode.
+// The caller must hold the read lock of the router.
+// If no writer exists for the level, then return an ErrUnknownLevel error.
+func (l *Logger) emit(level Level, obj interface{}) error {
+	position, line, err := l.format(level, obj)
 	if err != nil {
-		return errors.Wrap(err, "error writing info message")
+		return err
 	}
-	return nil
+	return l.writeFormatted(level, position, line)
 }
 
-// Info writes the provided object to the `info` writer.
-// If no `info` writer exists, then return an ErrUnknownLevel error.
-func (l *Logger) Info(obj interface{}) error {
-	level := "info"
-	position, ok := l.levels[level]
+// format returns the position of the writer for the given level and the object formatted in the format of that writer.
+// The caller must hold the read lock of the router.
+// If no writer exists for the level, then return an ErrUnknownLevel error.
+func (l *Logger) format(level Level, obj interface{}) (int, []byte, error) {
+	name := level.String()
+	position, ok := l.router.levels[name]
 	if !ok {
-		return &ErrUnknownLevel{Level: level}
+		return 0, nil, &ErrUnknownLevel{Level: name}
+	}
+	format := l.router.formats[position]
+	line, err := l.FormatObject(name, obj, format)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, fmt.Sprintf("error formating object at level %s using format %s", name, format))
+	}
+	return position, line, nil
+}
+
+// writeFormatted writes the formatted line to the writer at the given position, or queues it in async mode.
+// The caller must hold the read lock of the router.
+func (l *Logger) writeFormatted(level Level, position int, line []byte) error {
+	if len(line) == 0 {
+		return nil
+	}
+	writer := l.router.writers[position]
+	if aw, ok := writer.(*asyncWriter); ok {
+		err := aw.enqueue(level, string(line))
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error writing %s message", level))
+		}
+		return nil
 	}
-	_, err := l.WriteLineSafe(level, obj, l.writers[position], l.formats[position])
+	_, err := writer.WriteLineSafe(string(line))
 	if err != nil {
-		return errors.Wrap(err, "error writing info message")
+		return errors.Wrap(err, fmt.Sprintf("error writing %s message", level))
+	}
+	if l.AutoFlush {
+		err := writer.FlushSafe()
+		if err != nil {
+			return errors.Wrap(err, "error flushing after writing line")
+		}
 	}
 	return nil
 }
 
+// Trace writes the provided object to the `trace` writer.
+// If no `trace` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Trace(obj interface{}) error {
+	return l.write(LevelTrace, obj)
+}
+
+// TraceF writes the provided message to the `trace` writer.
+// The message is generated using `fmt.Sprintf(format, values...)`.
+// If no `trace` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) TraceF(format string, values ...interface{}) error {
+	return l.write(LevelTrace, fmt.Sprintf(format, values...))
+}
+
+// Debug writes the provided object to the `debug` writer.
+// If no `debug` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Debug(obj interface{}) error {
+	return l.write(LevelDebug, obj)
+}
+
+// DebugF writes the provided message to the `debug` writer.
+// The message is generated using `fmt.Sprintf(format, values...)`.
+// If no `debug` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) DebugF(format string, values ...interface{}) error {
+	return l.write(LevelDebug, fmt.Sprintf(format, values...))
+}
+
+// Info writes the provided object to the `info` writer.
+// If no `info` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Info(obj interface{}) error {
+	return l.write(LevelInfo, obj)
+}
+
 // InfoF writes the provided message to the `info` writer.
 // The message is generated using `fmt.Sprintf(format, values...)`.
 // If no `info` writer exists, then return an ErrUnknownLevel error.
 func (l *Logger) InfoF(format string, values ...interface{}) error {
-	level := "info"
-	position, ok := l.levels[level]
-	if !ok {
-		return &ErrUnknownLevel{Level: level}
-	}
-	_, err := l.WriteLineSafe(level, fmt.Sprintf(format, values...), l.writers[position], l.formats[position])
-	if err != nil {
-		return errors.Wrap(err, "error writing info message")
-	}
-	return nil
+	return l.write(LevelInfo, fmt.Sprintf(format, values...))
 }
 
 // Warn writes the provided object to the `warn` writer.
 // If no `warn` writer exists, then return an ErrUnknownLevel error.
 func (l *Logger) Warn(obj interface{}) error {
-	level := "warn"
-	position, ok := l.levels[level]
-	if !ok {
-		return &ErrUnknownLevel{Level: level}
-	}
-	_, err := l.WriteLineSafe(level, obj, l.writers[position], l.formats[position])
-	if err != nil {
-		return errors.Wrap(err, "error writing warn message")
-	}
-	return nil
+	return l.write(LevelWarn, obj)
 }
 
 // Error writes the provided object to the `error` writer.
 // If no `error` writer exists, then return an ErrUnknownLevel error.
 func (l *Logger) Error(obj interface{}) error {
-	level := "error"
-	position, ok := l.levels[level]
-	if !ok {
-		return &ErrUnknownLevel{Level: level}
-	}
-	_, err := l.WriteLineSafe(level, obj, l.writers[position], l.formats[position])
-	if err != nil {
-		return errors.Wrap(err, "error writing error message")
-	}
-	return nil
+	return l.write(LevelError, obj)
 }
 
 // ErrorF writes the provided message to the `error` writer.
 // The message is generated using `fmt.Sprintf(format, values...)`.
 // If no `error` writer exists, then return an ErrUnknownLevel error.
 func (l *Logger) ErrorF(format string, values ...interface{}) error {
-	level := "error"
-	position, ok := l.levels[level]
-	if !ok {
-		return &ErrUnknownLevel{Level: level}
-	}
-	_, err := l.WriteLineSafe(level, fmt.Sprintf(format, values...), l.writers[position], l.formats[position])
-	if err != nil {
-		return errors.Wrap(err, "error writing info message")
-	}
-	return nil
+	return l.write(LevelError, fmt.Sprintf(format, values...))
+}
+
+// Tracew writes the provided message with the given alternating keys and values to the `trace` writer.
+// Keys must be strings.  Values that are not part of a valid key-value pair are written under the BadKeyField.
+// If no `trace` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Tracew(msg string, keysAndValues ...interface{}) error {
+	return l.write(LevelTrace, l.keysAndValues(msg, keysAndValues))
+}
+
+// Debugw writes the provided message with the given alternating keys and values to the `debug` writer.
+// Keys must be strings.  Values that are not part of a valid key-value pair are written under the BadKeyField.
+// If no `debug` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) error {
+	return l.write(LevelDebug, l.keysAndValues(msg, keysAndValues))
+}
+
+// Infow writes the provided message with the given alternating keys and values to the `info` writer.
+// Keys must be strings.  Values that are not part of a valid key-value pair are written under the BadKeyField.
+// If no `info` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) error {
+	return l.write(LevelInfo, l.keysAndValues(msg, keysAndValues))
+}
+
+// Warnw writes the provided message with the given alternating keys and values to the `warn` writer.
+// Keys must be strings.  Values that are not part of a valid key-value pair are written under the BadKeyField.
+// If no `warn` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) error {
+	return l.write(LevelWarn, l.keysAndValues(msg, keysAndValues))
+}
+
+// Errorw writes the provided message with the given alternating keys and values to the `error` writer.
+// Keys must be strings.  Values that are not part of a valid key-value pair are written under the BadKeyField.
+// If no `error` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) error {
+	return l.write(LevelError, l.keysAndValues(msg, keysAndValues))
+}
+
+// Log writes the provided message with the given fields to the writer for the given level.
+// The fields are written from a pooled record and, for the json format, are encoded without boxing their values.
+// Log does not exit for LevelFatal, use Fatal instead.
+// If no writer exists for the level, then return an ErrUnknownLevel error.
+func (l *Logger) Log(level Level, msg string, fields ...Field) error {
+	r := fieldRecordPool.Get().(*fieldRecord)
+	r.msg = msg
+	r.fields = fields
+	err := l.write(level, r)
+	r.msg = ""
+	r.fields = nil
+	fieldRecordPool.Put(r)
+	return err
 }
 
-// Fatal locks all the writers, flushes them, writes the given message to the fatal writer, flushes the writers again, closes the writers, unlocks the writers, and finally exits with code 1.
+// AddShutdownHook registers a function that is run by Fatal after flushing the writers but before exiting, e.g., to close database connections.
+// Hooks are run in reverse order of registration and can still write messages.
+// If a hook returns an error, then the error is written to the error writer and the remaining hooks are still run.
+// Hooks are shared with child loggers.
+func (l *Logger) AddShutdownHook(hook func() error) {
+	l.hooks.add(hook)
+}
+
+// Fatal locks all the writers, flushes them, writes the given message to the error writer and the fatal writer, if different, flushes the writers again, and unlocks the writers.
+// Fatal then runs the shutdown hooks, closes the writers, and finally calls ExitFunc with ExitCode, which by default exits with code 1.
+// If sampling or the suppression of duplicates is on, then it is stopped and its summary is written before the message.
+// If ExitFunc returns, e.g., in tests, then Fatal returns.
 func (l *Logger) Fatal(obj interface{}) {
-	for _, w := range l.writers {
+	l.stopFilters()
+	l.router.RLock()
+	errorPosition, errorOk := l.router.levels["error"]
+	fatalPosition, fatalOk := l.router.levels["fatal"]
+	if l.AddCaller && (errorOk || fatalOk) {
+		l = l.withCaller()
+	}
+	for _, w := range l.router.writers {
 		w.Lock()
 	}
-	for _, w := range l.writers {
+	for _, w := range l.router.writers {
 		w.Flush() // #nosec
 	}
-	if position, ok := l.levels["error"]; ok {
-		l.WriteLine("fatal", obj, l.writers[position], l.formats[position]) // #nosec
+	if errorOk {
+		l.WriteLine("fatal", obj, l.router.writers[errorPosition], l.router.formats[errorPosition]) // #nosec
 	}
-	for _, w := range l.writers {
-		w.Flush() // #nosec
+	if fatalOk && (!errorOk || fatalPosition != errorPosition) {
+		l.WriteLine("fatal", obj, l.router.writers[fatalPosition], l.router.formats[fatalPosition]) // #nosec
 	}
-	for _, w := range l.writers {
-		w.Close() // #nosec
+	for _, w := range l.router.writers {
+		w.Flush() // #nosec
 	}
-	for _, w := range l.writers {
+	for _, w := range l.router.writers {
 		w.Unlock()
 	}
-	os.Exit(1)
+	l.router.RUnlock()
+	for _, hook := range l.hooks.list() {
+		err := hook()
+		if err != nil {
+			l.Error(errors.Wrap(err, "error running shutdown hook")) // #nosec
+		}
+	}
+	l.Close()
+	if l.ExitFunc != nil {
+		l.ExitFunc(l.ExitCode)
+	} else {
+		os.Exit(l.ExitCode)
+	}
 }
 
 // FatalF writes a message to the fatal writer using the provide format and values.
@@ -183,9 +597,49 @@ func (l *Logger) FatalF(format string, values ...interface{}) {
 	l.Fatal(fmt.Sprintf(format, values...))
 }
 
+// withContext returns a child logger bound to the fields attached to the given context.
+// If the context does not carry any fields, then returns the logger itself.
+func (l *Logger) withContext(ctx context.Context) *Logger {
+	if fields := FieldsFromContext(ctx); len(fields) > 0 {
+		return l.With(fields)
+	}
+	return l
+}
+
+// DebugContext writes the provided object to the `debug` writer including the fields attached to the context.
+// If no `debug` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) DebugContext(ctx context.Context, obj interface{}) error {
+	return l.withContext(ctx).Debug(obj)
+}
+
+// InfoContext writes the provided object to the `info` writer including the fields attached to the context.
+// If no `info` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) InfoContext(ctx context.Context, obj interface{}) error {
+	return l.withContext(ctx).Info(obj)
+}
+
+// WarnContext writes the provided object to the `warn` writer including the fields attached to the context.
+// If no `warn` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) WarnContext(ctx context.Context, obj interface{}) error {
+	return l.withContext(ctx).Warn(obj)
+}
+
+// ErrorContext writes the provided object to the `error` writer including the fields attached to the context.
+// If no `error` writer exists, then return an ErrUnknownLevel error.
+func (l *Logger) ErrorContext(ctx context.Context, obj interface{}) error {
+	return l.withContext(ctx).Error(obj)
+}
+
+// FatalContext calls Fatal with the fields attached to the context bound to the logger.
+func (l *Logger) FatalContext(ctx context.Context, obj interface{}) {
+	l.withContext(ctx).Fatal(obj)
+}
+
 // FlushSafe flushes all the writers using concurrency-safe methods.
 func (l *Logger) Flush() error {
-	for _, w := range l.writers {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	for _, w := range l.router.writers {
 		err := w.FlushSafe()
 		if err != nil {
 			return err
@@ -194,18 +648,38 @@ func (l *Logger) Flush() error {
 	return nil
 }
 
+// stopFilters turns off sampling and the suppression of duplicates and writes their summaries.
+func (l *Logger) stopFilters() {
+	l.router.Lock()
+	s := l.router.sampler
+	d := l.router.deduper
+	l.router.sampler = nil
+	l.router.deduper = nil
+	l.router.Unlock()
+	if d != nil {
+		d.stop()
+	}
+	if s != nil {
+		s.stop()
+	}
+}
+
 // Close locks all the writers, flushes them, closes them, and then unlocks them.
+// If sampling or the suppression of duplicates is on, then it is stopped and its summary is written first.
 func (l *Logger) Close() {
-	for _, w := range l.writers {
+	l.stopFilters()
+	l.router.RLock()
+	defer l.router.RUnlock()
+	for _, w := range l.router.writers {
 		w.Lock()
 	}
-	for _, w := range l.writers {
+	for _, w := range l.router.writers {
 		w.Flush() // #nosec
 	}
-	for _, w := range l.writers {
+	for _, w := range l.router.writers {
 		w.Close() // #nosec
 	}
-	for _, w := range l.writers {
+	for _, w := range l.router.writers {
 		w.Unlock()
 	}
 }
@@ -213,6 +687,7 @@ func (l *Logger) Close() {
 // ListenError listens for  message on a `chan interface{}` channel and writes them to the info writer.
 // If a *sync.WaitGroup is not nil, then it is marked as done once the channel is closed.
 func (l *Logger) ListenInfo(messages chan interface{}, wg *sync.WaitGroup) {
+	l = l.pinCaller()
 	go func(messages chan interface{}) {
 		for message := range messages {
 			err := l.Info(message)
@@ -230,6 +705,7 @@ func (l *Logger) ListenInfo(messages chan interface{}, wg *sync.WaitGroup) {
 // ListenError listens for  message on a `chan interface{}` channel and writes them to the error writer.
 // If a *sync.WaitGroup is not nil, then it is marked as done once the channel is closed.
 func (l *Logger) ListenError(messages chan interface{}, wg *sync.WaitGroup) {
+	l = l.pinCaller()
 	go func(messages chan interface{}) {
 		for message := range messages {
 			l.Error(message) // #nosec
@@ -242,8 +718,9 @@ func (l *Logger) ListenError(messages chan interface{}, wg *sync.WaitGroup) {
 }
 
 // ListenFatal listens for a message on a `chan interface{}` channel.
-// Once a message is received, the logger immediately calls l.Fatal(), which writes the fatal error, flushes the logs, closes the logs, and finally exits with code 1.
+// Once a message is received, the logger immediately calls l.Fatal(), which writes the fatal error, flushes the logs, runs the shutdown hooks, closes the logs, and finally exits.
 func (l *Logger) ListenFatal(messages chan interface{}) {
+	l = l.pinCaller()
 	go func() {
 		for msg := range messages {
 			l.Fatal(msg)
@@ -251,104 +728,41 @@ func (l *Logger) ListenFatal(messages chan interface{}) {
 	}()
 }
 
+// Writer returns an io.Writer that splits the bytes written to it into lines and writes each line as a message at the given level,
+// e.g., for libraries that only accept an io.Writer.
+// An incomplete line is buffered until the rest of the line is written, or written as is once longer than 64 KiB.
+// The returned writer also implements io.Closer, which writes the buffered incomplete line.
+// Messages at LevelFatal are written without exiting.
+func (l *Logger) Writer(level Level) io.Writer {
+	return &lineWriter{logger: l, level: level}
+}
+
+// StdLogger returns a standard library logger that writes each line as a message at the given level,
+// e.g., for the ErrorLog of an http.Server.
+// The standard library logger adds no prefix or flags, since the level and timestamp are added by the logger.
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
 // FormatObject formats a given object using a given level and format and returns the formatted bytes and error, if any.
+// Objects are copied into a new record with the level and timestamp, so the given object is never modified.
+// Structs are converted into records using their `gss` or `json` struct tags, while slices and other values are written under the object field.
+// Errors are written with their type, cause chain, and stack trace under the error field.
+// If a map contains a key used for the level or timestamp field, then the logger's collision policy is applied.
 func (l *Logger) FormatObject(level string, obj interface{}, format string) ([]byte, error) {
-	if err, ok := obj.(error); ok {
-		m := map[string]interface{}{}
-		h := make([]interface{}, 0)
-		if len(l.LevelField) > 0 {
-			m[l.LevelField] = level
-			h = append(h, l.LevelField)
-		}
-		if _, ok := m[l.TimeStampField]; !ok && len(l.TimeStampField) > 0 {
-			m[l.TimeStampField] = time.Now().Format(l.TimeStampFormat)
-			h = append(h, l.TimeStampField)
-		}
-		if len(l.MessageField) > 0 {
-			m[l.MessageField] = strings.Replace(err.Error(), "\n", ": ", -1)
-			h = append(h, l.MessageField)
-		}
-		return gss.SerializeBytes(&gss.SerializeBytesInput{
-			Object:            m,
-			Format:            format,
-			Header:            h,
-			ExpandHeader:      true,
-			Limit:             gss.NoLimit,
-			KeyValueSeparator: "=",
-			LineSeparator:     "\n",
-			Sorted:            true,
-			Pretty:            false,
-		})
-	} else if msg, ok := obj.(string); ok {
-		m := map[string]interface{}{}
-		h := make([]interface{}, 0)
-		if len(l.LevelField) > 0 {
-			m[l.LevelField] = level
-			h = append(h, l.LevelField)
-		}
-		if len(l.TimeStampField) > 0 {
-			m[l.TimeStampField] = time.Now().Format(l.TimeStampFormat)
-			h = append(h, l.TimeStampField)
-		}
-		if len(l.MessageField) > 0 {
-			m[l.MessageField] = msg
-			h = append(h, l.MessageField)
-		}
-		return gss.SerializeBytes(&gss.SerializeBytesInput{
-			Object:            m,
-			Format:            format,
-			Header:            h,
-			ExpandHeader:      true,
-			Limit:             gss.NoLimit,
-			KeyValueSeparator: "=",
-			LineSeparator:     "\n",
-			Sorted:            true,
-			Pretty:            false,
-		})
-	} else if m, ok := obj.(map[string]string); ok {
-		h := make([]interface{}, 0)
-		if len(l.LevelField) > 0 {
-			m[l.LevelField] = level
-			h = append(h, l.LevelField)
-		}
-		if len(l.TimeStampField) > 0 {
-			m[l.TimeStampField] = time.Now().Format(l.TimeStampFormat)
-			h = append(h, l.TimeStampField)
-		}
-		return gss.SerializeBytes(&gss.SerializeBytesInput{
-			Object:            m,
-			Format:            format,
-			Header:            h,
-			ExpandHeader:      true,
-			Limit:             gss.NoLimit,
-			KeyValueSeparator: "=",
-			LineSeparator:     "\n",
-			Sorted:            true,
-			Pretty:            false,
-		})
-	} else if m, ok := obj.(map[string]interface{}); ok {
-		if len(l.LevelField) > 0 {
-			m[l.LevelField] = level
-		}
-		if len(l.TimeStampField) > 0 {
-			m[l.TimeStampField] = time.Now().Format(l.TimeStampFormat)
+	if r, ok := obj.(*fieldRecord); ok && format == "json" {
+		if b, ok := r.appendJSON(make([]byte, 0, 256), l, level); ok {
+			return b, nil
 		}
-		return gss.SerializeBytes(&gss.SerializeBytesInput{
-			Object:            m,
-			Format:            format,
-			Header:            gss.NoHeader,
-			ExpandHeader:      true,
-			Limit:             gss.NoLimit,
-			KeyValueSeparator: "=",
-			LineSeparator:     "\n",
-			Sorted:            true,
-			Pretty:            false,
-		})
+	}
+	m, h := l.newRecord(level, obj, format)
+	if h == nil {
+		h = gss.NoHeader
 	}
 	return gss.SerializeBytes(&gss.SerializeBytesInput{
-		Object:            obj,
+		Object:            m,
 		Format:            format,
-		Header:            gss.NoHeader,
+		Header:            h,
 		ExpandHeader:      true,
 		Limit:             gss.NoLimit,
 		KeyValueSeparator: "=",
@@ -358,6 +772,21 @@ func (l *Logger) FormatObject(level string, obj interface{}, format string) ([]b
 	})
 }
 
+// isFileURI returns true if the uri refers to a local file that can be reopened.
+func isFileURI(uri string) bool {
+	switch uri {
+	case "", "-", "stdout", "stderr", "null":
+		return false
+	}
+	if strings.HasPrefix(uri, "/dev/") {
+		return false
+	}
+	if strings.HasPrefix(uri, "file://") {
+		return true
+	}
+	return !strings.Contains(uri, "://")
+}
+
 // WriteLine formats the given object using FormatObject then writes the formatted string with a trailing newline to the matching grw.ByteWriteCloser and returns an error, if any.
 // WriteLine calls the writer's WriteLine method, which does not lock the underlying writer.
 // The writer can already be locked.
@@ -10,9 +10,14 @@ package gsl
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
@@ -140,3 +145,638 @@ func TestLoggerInfoAutoFlushTagsMap(t *testing.T) {
 		assert.Equal(t, "z", m["c"])
 	}
 }
+
+func TestLoggerWith(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := false
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	child := l.With(map[string]interface{}{"request_id": "abc"}).With(map[string]interface{}{"job": "etl"})
+
+	in := map[string]interface{}{"a": "x"}
+	err := child.Info(in)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "x"}, in)
+
+	err = child.Flush()
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "info", outObject["level"])
+	assert.Equal(t, "x", outObject["a"])
+	assert.Equal(t, "abc", outObject["request_id"])
+	assert.Equal(t, "etl", outObject["job"])
+
+	b.Reset()
+
+	err = child.Info(struct {
+		Name string `json:"name"`
+	}{Name: "y"})
+	assert.NoError(t, err)
+
+	err = child.Flush()
+	assert.NoError(t, err)
+
+	outObject = map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "y", outObject["name"])
+	assert.Equal(t, "abc", outObject["request_id"])
+	assert.Equal(t, "etl", outObject["job"])
+}
+
+func TestLoggerMinLevel(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"debug": 0, "info": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+	l.SetMinLevel(LevelInfo)
+
+	err := l.Debug(testMessage)
+	assert.NoError(t, err)
+	assert.Empty(t, b.Bytes())
+
+	err = l.Trace(testMessage)
+	assert.NoError(t, err)
+	assert.Empty(t, b.Bytes())
+
+	err = l.Warn(testMessage)
+	assert.IsType(t, &ErrUnknownLevel{}, err)
+
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, b.Bytes())
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	err := l.Debug(testMessage)
+	assert.IsType(t, &ErrUnknownLevel{}, err)
+
+	err = l.SetLevel(LevelDebug, 1)
+	assert.IsType(t, &ErrInvalidPosition{}, err)
+
+	err = l.SetLevel(LevelDebug, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"debug": 0, "info": 0}, l.Levels())
+
+	err = l.Debug(testMessage)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, b.Bytes())
+
+	l.DisableLevel(LevelDebug)
+	assert.Equal(t, map[string]int{"info": 0}, l.Levels())
+
+	err = l.Debug(testMessage)
+	assert.IsType(t, &ErrUnknownLevel{}, err)
+
+	err = l.EnableLevel(LevelDebug)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"debug": 0, "info": 0}, l.Levels())
+
+	err = l.EnableLevel(LevelWarn)
+	assert.IsType(t, &ErrUnknownLevel{}, err)
+}
+
+func TestLoggerSetLevelConcurrent(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0, "error": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	messages := make(chan interface{})
+	l.ListenInfo(messages, wg)
+
+	for i := 0; i < 100; i++ {
+		messages <- testMessage
+		if i%2 == 0 {
+			l.DisableLevel(LevelInfo)
+			l.SetMinLevel(LevelDebug)
+		} else {
+			err := l.EnableLevel(LevelInfo)
+			assert.NoError(t, err)
+			l.SetMinLevel(LevelInfo)
+		}
+	}
+	close(messages)
+	wg.Wait()
+}
+
+func TestLoggerFatal(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"error": 0, "fatal": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := false
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	exitCode := -1
+	l.ExitFunc = func(code int) {
+		exitCode = code
+	}
+	l.ExitCode = 2
+
+	hooks := make([]string, 0)
+	l.AddShutdownHook(func() error {
+		hooks = append(hooks, "a")
+		return nil
+	})
+	l.AddShutdownHook(func() error {
+		hooks = append(hooks, "b")
+		return nil
+	})
+
+	l.Fatal(testMessage)
+
+	assert.Equal(t, 2, exitCode)
+	assert.Equal(t, []string{"b", "a"}, hooks)
+
+	outObject := map[string]interface{}{}
+	err := json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "fatal", outObject["level"])
+	assert.Equal(t, testMessage, outObject["msg"])
+}
+
+func TestLoggerInfow(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	levels := map[string]int{"info": 0}
+	writers := []Writer{w}
+	formats := []string{"json"}
+	autoFlush := true
+
+	l := NewLogger(levels, writers, formats, autoFlush)
+
+	err := l.Infow(testMessage, "count", 3, 4, "name", "y", "trailing")
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "info", outObject["level"])
+	assert.Equal(t, testMessage, outObject["msg"])
+	assert.Equal(t, float64(3), outObject["count"])
+	assert.Equal(t, "y", outObject["name"])
+	assert.Equal(t, []interface{}{float64(4), "trailing"}, outObject[BadKeyField])
+}
+
+func TestLoggerFormatObjectCollision(t *testing.T) {
+
+	l := NewLogger(map[string]int{}, []Writer{}, []string{}, false)
+	l.TimeStampField = ""
+
+	in := map[string]string{"level": "user", "a": "x"}
+
+	testCases := []struct {
+		collision CollisionPolicy
+		expected  map[string]interface{}
+	}{
+		{
+			collision: CollisionOverwrite,
+			expected:  map[string]interface{}{"level": "info", "a": "x"},
+		},
+		{
+			collision: CollisionPrefix,
+			expected:  map[string]interface{}{"level": "info", "fields.level": "user", "a": "x"},
+		},
+		{
+			collision: CollisionNest,
+			expected:  map[string]interface{}{"level": "info", "fields": map[string]interface{}{"level": "user"}, "a": "x"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		l.Collision = testCase.collision
+		b, err := l.FormatObject("info", in, "json")
+		assert.NoError(t, err)
+		outObject := map[string]interface{}{}
+		err = json.Unmarshal(b, &outObject)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, outObject)
+		assert.Equal(t, map[string]string{"level": "user", "a": "x"}, in)
+	}
+}
+
+func TestLoggerFormatObjectCollisionFields(t *testing.T) {
+
+	l := NewLogger(map[string]int{}, []Writer{}, []string{}, false)
+	l.TimeStampField = ""
+
+	testCases := []struct {
+		collision CollisionPolicy
+		expected  map[string]interface{}
+	}{
+		{
+			collision: CollisionPrefix,
+			expected: map[string]interface{}{
+				"level":               "info",
+				"fields":              map[string]interface{}{"a": "x"},
+				"fields.level":        "taken",
+				"fields.fields.level": "user",
+			},
+		},
+		{
+			collision: CollisionNest,
+			expected: map[string]interface{}{
+				"level":        "info",
+				"fields":       map[string]interface{}{"a": "x", "level": "user"},
+				"fields.level": "taken",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		l.Collision = testCase.collision
+		// the result must not depend on the order the map is iterated
+		for i := 0; i < 10; i++ {
+			fields := map[string]interface{}{"a": "x"}
+			in := map[string]interface{}{"level": "user", "fields": fields, "fields.level": "taken"}
+			b, err := l.FormatObject("info", in, "json")
+			assert.NoError(t, err)
+			outObject := map[string]interface{}{}
+			err = json.Unmarshal(b, &outObject)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, outObject)
+			assert.Equal(t, map[string]interface{}{"a": "x"}, fields)
+		}
+	}
+}
+
+// TestEmbedded is an exported struct embedded by pointer in tests.
+type TestEmbedded struct {
+	ID string `json:"id"`
+}
+
+// testPointerMarshaler implements json.Marshaler with a pointer receiver.
+type testPointerMarshaler struct {
+	Value string
+}
+
+func (p *testPointerMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal("marshaled:" + p.Value)
+}
+
+func TestLoggerFormatObjectStruct(t *testing.T) {
+
+	l := NewLogger(map[string]int{}, []Writer{}, []string{}, false)
+
+	type embedded struct {
+		ID string `json:"id"`
+	}
+
+	in := &struct {
+		embedded
+		Name    string `gss:"name" json:"full_name"`
+		Count   int    `json:"count,omitempty"`
+		Skipped string `json:"-"`
+		private string
+	}{embedded: embedded{ID: "1"}, Name: "x", Skipped: "y", private: "z"}
+
+	testCases := []struct {
+		obj      interface{}
+		expected map[string]interface{}
+	}{
+		{
+			obj:      in,
+			expected: map[string]interface{}{"level": "info", "id": "1", "name": "x"},
+		},
+		{
+			obj: &struct {
+				*TestEmbedded
+				Name string `json:"name"`
+			}{Name: "x"},
+			expected: map[string]interface{}{"level": "info", "name": "x"},
+		},
+		{
+			obj:      &testPointerMarshaler{Value: "x"},
+			expected: map[string]interface{}{"level": "info", "obj": "marshaled:x"},
+		},
+		{
+			obj:      []string{"a", "b"},
+			expected: map[string]interface{}{"level": "info", "obj": []interface{}{"a", "b"}},
+		},
+		{
+			obj:      3,
+			expected: map[string]interface{}{"level": "info", "obj": float64(3)},
+		},
+		{
+			obj:      nil,
+			expected: map[string]interface{}{"level": "info", "obj": nil},
+		},
+	}
+
+	for _, testCase := range testCases {
+		b, err := l.FormatObject("info", testCase.obj, "json")
+		assert.NoError(t, err)
+		outObject := map[string]interface{}{}
+		err = json.Unmarshal(b, &outObject)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, outObject["ts"])
+		delete(outObject, "ts")
+		assert.Equal(t, testCase.expected, outObject)
+	}
+
+	// for every format, a struct is formatted the same as the equivalent map
+	l.TimeStampField = ""
+	for _, format := range Formats {
+		expected, err := l.FormatObject("info", map[string]interface{}{"id": "1", "name": "x"}, format)
+		assert.NoError(t, err)
+		b, err := l.FormatObject("info", in, format)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(b), format)
+	}
+}
+
+func TestLoggerFormatObjectError(t *testing.T) {
+
+	l := NewLogger(map[string]int{}, []Writer{}, []string{}, false)
+
+	wrapped := errors.Wrap(fmt.Errorf("connection refused"), "error connecting to database")
+
+	b, err := l.FormatObject("error", wrapped, "json")
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b, &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "error connecting to database: connection refused", outObject["msg"])
+	assert.Equal(t, "*errors.errorString", outObject["error.type"])
+	assert.Equal(t, []interface{}{"error connecting to database", "connection refused"}, outObject["error.chain"])
+	assert.NotEmpty(t, outObject["error.stack"])
+
+	l.ErrorChainDepth = 1
+	l.ErrorStackFormats = []string{}
+
+	b, err = l.FormatObject("error", wrapped, "json")
+	assert.NoError(t, err)
+
+	outObject = map[string]interface{}{}
+	err = json.Unmarshal(b, &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"error connecting to database"}, outObject["error.chain"])
+	assert.Nil(t, outObject["error.stack"])
+}
+
+// testSelfError is an error that wraps itself.
+type testSelfError struct{}
+
+func (e *testSelfError) Error() string {
+	return "loop"
+}
+
+func (e *testSelfError) Unwrap() error {
+	return e
+}
+
+func TestLoggerFormatObjectErrorCycle(t *testing.T) {
+
+	l := NewLogger(map[string]int{}, []Writer{}, []string{}, false)
+
+	b, err := l.FormatObject("error", errors.Wrap(&testSelfError{}, "failed"), "json")
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b, &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "failed: loop", outObject["msg"])
+	assert.Equal(t, "*gsl.testSelfError", outObject["error.type"])
+	assert.Equal(t, []interface{}{"failed"}, outObject["error.chain"])
+}
+
+func TestLoggerAddCallerFiltered(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+	l.AddCaller = true
+	l.AddFunc = true
+
+	// the caller is not looked up for messages below the minimum level
+	withCaller := testing.AllocsPerRun(100, func() {
+		l.Debug(testMessage) // #nosec
+	})
+	l.AddCaller = false
+	withoutCaller := testing.AllocsPerRun(100, func() {
+		l.Debug(testMessage) // #nosec
+	})
+	assert.Equal(t, withoutCaller, withCaller)
+}
+
+func TestLoggerAddCaller(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+	l.AddCaller = true
+	l.AddFunc = true
+
+	err := l.InfoF("hello %s", "world")
+	assert.NoError(t, err)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Contains(t, outObject["caller"], "gsl/Logger_test.go:")
+	assert.Contains(t, outObject["func"], "TestLoggerAddCaller")
+
+	b.Reset()
+
+	messages := make(chan interface{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		l.ListenInfo(messages, wg)
+	}()
+	messages <- testMessage
+	close(messages)
+	wg.Wait()
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	outObject = map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Contains(t, outObject["caller"], "gsl/Logger_test.go:")
+}
+
+func TestLoggerStdLogger(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"error": 0}, []Writer{w}, []string{"json"}, false)
+	l.AddCaller = true
+
+	l.StdLogger(LevelError).Printf("http: TLS handshake error from %s", "127.0.0.1")
+
+	err := l.Flush()
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "error", outObject["level"])
+	assert.Equal(t, "http: TLS handshake error from 127.0.0.1", outObject["msg"])
+	assert.Contains(t, outObject["caller"], "gsl/Logger_test.go:")
+
+	b.Reset()
+
+	lw := l.Writer(LevelError)
+	_, err = lw.Write([]byte("first\r\nsec"))
+	assert.NoError(t, err)
+	_, err = lw.Write([]byte("ond\n\nthi"))
+	assert.NoError(t, err)
+	err = lw.(io.Closer).Close()
+	assert.NoError(t, err)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	messages := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		outObject := map[string]interface{}{}
+		err = json.Unmarshal([]byte(line), &outObject)
+		assert.NoError(t, err)
+		messages = append(messages, fmt.Sprint(outObject["msg"]))
+	}
+	assert.Equal(t, []string{"first", "second", "thi"}, messages)
+
+	b.Reset()
+
+	// an incomplete line longer than the maximum is written without waiting for a newline
+	long := strings.Repeat("x", maxLineLength+1)
+	n, err := lw.Write([]byte(long))
+	assert.NoError(t, err)
+	assert.Equal(t, len(long), n)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	outObject = map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+	assert.Equal(t, long, outObject["msg"])
+}
+
+func TestLoggerWriterError(t *testing.T) {
+
+	w, _ := grw.WriteMemoryBytes()
+
+	// lines at an unknown level fail, so the writer reports the bytes consumed up to the first line
+	l := NewLogger(map[string]int{"error": 0}, []Writer{w}, []string{"json"}, false)
+
+	n, err := l.Writer(LevelInfo).Write([]byte("first\nsecond\n"))
+	assert.Error(t, err)
+	assert.Equal(t, len("first\n"), n)
+}
+
+func TestLoggerLimits(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "warn": 0}, []Writer{w}, []string{"json"}, false)
+
+	for i := 0; i < 5; i++ {
+		err := l.Once("deprecated").Warn("once")
+		assert.NoError(t, err)
+		err = l.With(map[string]interface{}{"i": i}).Every(2, "retry").Info("every")
+		assert.NoError(t, err)
+		err = l.Throttle("retry", time.Hour).Warn("throttle")
+		assert.NoError(t, err)
+	}
+
+	err := l.Flush()
+	assert.NoError(t, err)
+
+	messages := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		outObject := map[string]interface{}{}
+		err = json.Unmarshal([]byte(line), &outObject)
+		assert.NoError(t, err)
+		messages = append(messages, fmt.Sprint(outObject["msg"]))
+	}
+	assert.Equal(t, []string{"once", "every", "throttle", "every", "every"}, messages)
+}
+
+func TestLoggerThrottle(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.limits.clock = func() time.Time {
+		return now
+	}
+
+	for _, step := range []time.Duration{0, 30 * time.Second, 30 * time.Second, time.Minute} {
+		now = now.Add(step)
+		err := l.Throttle("retry", time.Minute).Info(now.Format(time.Kitchen))
+		assert.NoError(t, err)
+	}
+
+	err := l.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"12:00AM", "12:01AM", "12:02AM"}, readMessages(t, b.String()))
+
+	// throttle rules whose interval has passed are evicted once the number of rules doubles
+	for i := 1; i < minLimitSweep; i++ {
+		err := l.Throttle(fmt.Sprint(i), time.Minute).Info("throttle")
+		assert.NoError(t, err)
+	}
+	now = now.Add(time.Minute)
+	l.Throttle("last", time.Minute) // #nosec
+	assert.Len(t, l.limits.rules, 1)
+
+	// calls with the same key and different parameters use separate rules
+	l.Throttle("last", time.Hour) // #nosec
+	assert.Len(t, l.limits.rules, 2)
+}
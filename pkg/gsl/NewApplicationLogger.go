@@ -0,0 +1,137 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+// NewApplicationLogger creates a new *Logger given the fields in *CreateApplicationLoggerInput and returns an error, if any.
+// The logger is configured the same as a logger created by CreateApplicationLogger, but configuration problems are returned rather than exiting.
+// If levels that share a destination use different formats or compression algorithms, then returns an ErrFormatMismatch or ErrCompressionMismatch error.
+// If a level is configured more than once, then returns an ErrDuplicateLevel error.
+// If a writer cannot be created, then returns an ErrWriterCreate error.
+// If an error is returned, then any writers already created are closed.
+func NewApplicationLogger(input *CreateApplicationLoggerInput) (*Logger, error) {
+
+	destinations := input.Destinations
+	verboseLevels := map[string]bool{}
+	if len(destinations) == 0 {
+		errorDestination := LevelDestination{
+			Destination: input.ErrorDestination,
+			Compression: input.ErrorCompression,
+			Format:      input.ErrorFormat,
+		}
+		infoDestination := LevelDestination{
+			Destination: input.InfoDestination,
+			Compression: input.InfoCompression,
+			Format:      input.InfoFormat,
+		}
+		destinations = []LevelDestination{
+			errorDestination.withLevel("error"),
+			errorDestination.withLevel("fatal"),
+			errorDestination.withLevel("warn"),
+			infoDestination.withLevel("info"),
+			infoDestination.withLevel("debug"),
+		}
+		verboseLevels["warn"] = true
+		verboseLevels["debug"] = true
+	}
+
+	// Validate the destinations before creating any writers.
+	levels := map[string]int{}
+	seen := map[string]bool{}      // levels already configured, including null destinations
+	positions := map[string]int{}  // destination --> position in writers
+	shared := []LevelDestination{} // first level destination for each position
+	for _, d := range destinations {
+		level, err := ParseLevel(d.Level)
+		if err != nil {
+			return nil, err
+		}
+		name := level.String()
+		if seen[name] {
+			return nil, &ErrDuplicateLevel{Level: name}
+		}
+		seen[name] = true
+		if isNullDestination(d.Destination) {
+			continue
+		}
+		position, ok := positions[d.Destination]
+		if !ok {
+			position = len(shared)
+			positions[d.Destination] = position
+			shared = append(shared, d.withLevel(name))
+		} else {
+			first := shared[position]
+			if d.Format != first.Format {
+				return nil, &ErrFormatMismatch{
+					Destination: d.Destination,
+					Level:       name,
+					Format:      d.Format,
+					OtherLevel:  first.Level,
+					OtherFormat: first.Format,
+				}
+			}
+			if d.Compression != first.Compression {
+				return nil, &ErrCompressionMismatch{
+					Destination:      d.Destination,
+					Level:            name,
+					Compression:      d.Compression,
+					OtherLevel:       first.Level,
+					OtherCompression: first.Compression,
+				}
+			}
+		}
+		levels[name] = position
+	}
+
+	writers := make([]Writer, 0, len(shared))
+	formats := make([]string, 0, len(shared))
+	writerDestinations := make([]destination, 0, len(shared))
+	for _, d := range shared {
+		w, err := grw.WriteToResource(&grw.WriteToResourceInput{
+			Uri:      d.Destination,
+			Alg:      d.Compression,
+			Dict:     grw.NoDict,
+			Append:   true,
+			S3Client: nil,
+		})
+		if err != nil {
+			for _, w := range writers {
+				w.Close() // #nosec
+			}
+			return nil, &ErrWriterCreate{Destination: d.Destination, Err: err}
+		}
+		writers = append(writers, w)
+		formats = append(formats, d.Format)
+		writerDestinations = append(writerDestinations, destination{uri: d.Destination, compression: d.Compression})
+	}
+
+	logger := NewLogger(map[string]int{}, writers, formats, true)
+	for level, position := range levels {
+		if verboseLevels[level] {
+			logger.router.verboseLevels[level] = position
+		} else {
+			logger.router.levels[level] = position
+		}
+	}
+	logger.router.destinations = writerDestinations
+	logger.SetVerbose(input.Verbose)
+	return logger, nil
+}
+
+// withLevel returns a copy of the level destination for the given level.
+func (d LevelDestination) withLevel(level string) LevelDestination {
+	d.Level = level
+	return d
+}
+
+// isNullDestination returns true if messages sent to the destination should be discarded.
+func isNullDestination(uri string) bool {
+	return len(uri) == 0 || uri == "/dev/null" || uri == "null"
+}
@@ -0,0 +1,166 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApplicationLogger(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: filepath.Join(dir, "error.log"),
+		ErrorFormat:      "json",
+		InfoDestination:  filepath.Join(dir, "info.log"),
+		InfoFormat:       "tags",
+		Verbose:          true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"debug": 1, "info": 1, "warn": 0, "error": 0, "fatal": 0}, l.Levels())
+	assert.Equal(t, []string{"json", "tags"}, l.Formats())
+	l.Close()
+}
+
+func TestNewApplicationLoggerFormatMismatch(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: path,
+		ErrorFormat:      "json",
+		InfoDestination:  path,
+		InfoFormat:       "tags",
+	})
+	assert.Nil(t, l)
+	assert.IsType(t, &ErrFormatMismatch{}, err)
+}
+
+func TestNewApplicationLoggerCompressionMismatch(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: path,
+		ErrorFormat:      "json",
+		InfoDestination:  path,
+		InfoFormat:       "json",
+		InfoCompression:  "gzip",
+	})
+	assert.Nil(t, l)
+	assert.IsType(t, &ErrCompressionMismatch{}, err)
+}
+
+func TestNewApplicationLoggerWriterCreate(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		ErrorDestination: filepath.Join(dir, "missing", "error.log"),
+		ErrorFormat:      "json",
+	})
+	assert.Nil(t, l)
+	assert.IsType(t, &ErrWriterCreate{}, err)
+}
+
+func TestNewApplicationLoggerDestinations(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	errorPath := filepath.Join(dir, "error.log")
+	alertPath := filepath.Join(dir, "alert.log")
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		Destinations: []LevelDestination{
+			{Level: "debug", Destination: filepath.Join(dir, "debug.log"), Format: "json"},
+			{Level: "info", Destination: "null", Format: "json"},
+			{Level: "warn", Destination: errorPath, Format: "json"},
+			{Level: "error", Destination: errorPath, Format: "json"},
+			{Level: "fatal", Destination: alertPath, Format: "json"},
+		},
+		Verbose: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"debug": 0, "warn": 1, "error": 1, "fatal": 2}, l.Levels())
+
+	l.ExitFunc = func(code int) {}
+	l.Fatal(testMessage)
+
+	errorBytes, err := ioutil.ReadFile(errorPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, errorBytes)
+
+	alertBytes, err := ioutil.ReadFile(alertPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, alertBytes)
+}
+
+func TestNewApplicationLoggerDestinationsMismatch(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		Destinations: []LevelDestination{
+			{Level: "Warning", Destination: path, Format: "json"},
+			{Level: "error", Destination: path, Format: "tags"},
+		},
+	})
+	assert.Nil(t, l)
+	assert.Equal(t, &ErrFormatMismatch{Destination: path, Level: "error", Format: "tags", OtherLevel: "warn", OtherFormat: "json"}, err)
+
+	l, err = NewApplicationLogger(&CreateApplicationLoggerInput{
+		Destinations: []LevelDestination{
+			{Level: "warn", Destination: path, Format: "json"},
+			{Level: "warn", Destination: path, Format: "json"},
+		},
+	})
+	assert.Nil(t, l)
+	assert.IsType(t, &ErrDuplicateLevel{}, err)
+}
+
+func TestNewApplicationLoggerDuplicateNullLevel(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "app.log")
+
+	l, err := NewApplicationLogger(&CreateApplicationLoggerInput{
+		Destinations: []LevelDestination{
+			{Level: "debug", Destination: "/dev/null"},
+			{Level: "debug", Destination: path, Format: "json"},
+		},
+	})
+	assert.Nil(t, l)
+	assert.Equal(t, &ErrDuplicateLevel{Level: "debug"}, err)
+}
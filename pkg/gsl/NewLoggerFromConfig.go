@@ -0,0 +1,32 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// NewLoggerFromConfig validates the configuration, creates the writers, and returns a new *Logger.
+// If the configuration is invalid, then returns an ErrInvalidConfig error that points to the offending value.
+// If an error is returned, then any writers already created are closed.
+func NewLoggerFromConfig(c *Config) (*Logger, error) {
+	logger := NewLogger(map[string]int{}, []Writer{}, []string{}, c.AutoFlush == nil || *c.AutoFlush)
+	err := logger.ApplyConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.LevelField) > 0 {
+		logger.LevelField = c.LevelField
+	}
+	if len(c.TimeStampField) > 0 {
+		logger.TimeStampField = c.TimeStampField
+	}
+	if len(c.TimeStampFormat) > 0 {
+		logger.TimeStampFormat = c.TimeStampFormat
+	}
+	if len(c.MessageField) > 0 {
+		logger.MessageField = c.MessageField
+	}
+	return logger, nil
+}
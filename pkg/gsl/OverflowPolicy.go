@@ -0,0 +1,18 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// OverflowPolicy determines what happens when a message is written in async mode and the queue for the writer is full.
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = iota // wait until the queue has space
+	OverflowDropNewest                       // drop the new message
+	OverflowDropOldest                       // drop the oldest message in the queue to make space
+	OverflowDropBelow                        // drop the new message if it is below the drop level, otherwise wait until the queue has space
+)
@@ -0,0 +1,28 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"time"
+)
+
+const (
+	DefaultSamplingInterval = time.Second // the default interval used for sampling
+	DefaultSamplingKeys     = 4096        // the default number of messages counted separately in each interval
+)
+
+// SamplingInput configures the sampling of messages by a logger.
+// In each interval, the first messages for each level and message are written and then only every Thereafter-th message is written.
+// Once Keys messages are counted in an interval, other messages are counted together for each level, which bounds the memory used.
+type SamplingInput struct {
+	Interval   time.Duration // the length of each interval, defaults to DefaultSamplingInterval
+	First      int           // the number of messages written for each level and message in each interval
+	Thereafter int           // after the first messages, write every Thereafter-th message, or none if zero
+	Levels     []Level       // the levels that are sampled, defaults to all levels
+	Keys       int           // the number of messages counted separately in each interval, defaults to DefaultSamplingKeys
+}
@@ -0,0 +1,62 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"github.com/pkg/errors"
+)
+
+// SetAsync turns on async mode using the given configuration or turns it off if input is nil.
+// In async mode, messages are formatted by the caller and then queued in a bounded ring buffer for each writer,
+// which a background goroutine writes to the writer and flushes, so a slow writer does not block the caller.
+// When a queue is full, the input's overflow policy determines whether the caller waits or the message is dropped.
+// Use Dropped to get the number of dropped messages.
+//
+// Flush, Close, and Fatal write all the queued messages before returning.
+// Writers created later by ApplyConfig or Reopen also use async mode.
+// If async mode is already on, then the queued messages are written before the new configuration is used.
+// Async mode is shared with child loggers.
+// If the queued messages cannot be written, then async mode is still changed and the first error is returned.
+func (l *Logger) SetAsync(input *AsyncInput) error {
+	r := l.router
+	r.Lock()
+	defer r.Unlock()
+	if input != nil {
+		async := *input
+		r.async = &async
+	} else {
+		r.async = nil
+	}
+	var first error
+	for i, w := range r.writers {
+		if aw, ok := w.(*asyncWriter); ok {
+			aw.Lock()
+			if err := aw.stop(); err != nil && first == nil {
+				first = errors.Wrap(err, "error writing queued messages")
+			}
+			aw.Unlock()
+			w = aw.writer
+		}
+		r.writers[i] = r.wrap(w)
+	}
+	return first
+}
+
+// Async returns true if async mode is on.
+func (l *Logger) Async() bool {
+	l.router.RLock()
+	defer l.router.RUnlock()
+	return l.router.async != nil
+}
+
+// Dropped returns the number of messages dropped by the overflow policy in async mode by level name, e.g., {"debug": 12}.
+// Levels with no dropped messages are omitted.
+// The counts include messages dropped before async mode was last changed.
+func (l *Logger) Dropped() map[string]uint64 {
+	return l.router.dropped.snapshot()
+}
@@ -0,0 +1,214 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+// blockingWriter is a Writer that blocks writing lines until the gate is opened.
+// The started channel receives a signal when a line starts being written.
+type blockingWriter struct {
+	Writer
+	gate    chan struct{}
+	started chan struct{}
+}
+
+func newBlockingWriter(w Writer) *blockingWriter {
+	return &blockingWriter{Writer: w, gate: make(chan struct{}), started: make(chan struct{}, 1)}
+}
+
+func (w *blockingWriter) WriteLine(str string) (int, error) {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-w.gate
+	return w.Writer.WriteLine(str)
+}
+
+func readMessages(t *testing.T, str string) []string {
+	messages := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(str), "\n") {
+		outObject := map[string]interface{}{}
+		err := json.Unmarshal([]byte(line), &outObject)
+		assert.NoError(t, err)
+		messages = append(messages, fmt.Sprint(outObject["msg"]))
+	}
+	return messages
+}
+
+func TestLoggerSetAsync(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+	err := l.SetAsync(&AsyncInput{Size: 4, Policy: OverflowBlock})
+	assert.NoError(t, err)
+	assert.True(t, l.Async())
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				err := l.InfoF("%d-%d", i, j)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	assert.Len(t, readMessages(t, b.String()), 100)
+	assert.Empty(t, l.Dropped())
+
+	err = l.SetAsync(nil)
+	assert.NoError(t, err)
+	assert.False(t, l.Async())
+	_, ok := l.router.writers[0].(*asyncWriter)
+	assert.False(t, ok)
+}
+
+func TestLoggerSetAsyncDrop(t *testing.T) {
+
+	testCases := []struct {
+		policy   OverflowPolicy
+		expected []string
+		dropped  map[string]uint64
+	}{
+		{policy: OverflowDropNewest, expected: []string{"0", "1", "2", "error"}, dropped: map[string]uint64{"info": 3}},
+		{policy: OverflowDropOldest, expected: []string{"0", "4", "5", "error"}, dropped: map[string]uint64{"info": 3}},
+	}
+
+	for _, testCase := range testCases {
+		w, b := grw.WriteMemoryBytes()
+		bw := newBlockingWriter(w)
+
+		l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{bw}, []string{"json"}, false)
+		err := l.SetAsync(&AsyncInput{Size: 2, Policy: testCase.policy})
+		assert.NoError(t, err)
+
+		// the goroutine takes the first message and blocks writing it
+		err = l.Info("0")
+		assert.NoError(t, err)
+		<-bw.started
+
+		for i := 1; i < 6; i++ {
+			err := l.InfoF("%d", i)
+			assert.NoError(t, err)
+		}
+
+		close(bw.gate)
+
+		err = l.Flush()
+		assert.NoError(t, err)
+
+		err = l.Error("error")
+		assert.NoError(t, err)
+
+		err = l.Flush()
+		assert.NoError(t, err)
+
+		assert.Equal(t, testCase.expected, readMessages(t, b.String()))
+		assert.Equal(t, testCase.dropped, l.Dropped())
+	}
+}
+
+func TestLoggerSetAsyncFatal(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+	err := l.SetAsync(&AsyncInput{})
+	assert.NoError(t, err)
+	l.ExitFunc = func(code int) {}
+
+	for i := 0; i < 10; i++ {
+		err := l.InfoF("%d", i)
+		assert.NoError(t, err)
+	}
+	l.Fatal("fatal")
+
+	m := readMessages(t, b.String())
+	assert.Len(t, m, 11)
+	assert.Equal(t, "fatal", m[10])
+}
+
+func TestLoggerSetAsyncPriority(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+	bw := newBlockingWriter(w)
+
+	l := NewLogger(map[string]int{"debug": 0, "info": 0, "error": 0}, []Writer{bw}, []string{"json"}, false)
+	err := l.SetAsync(&AsyncInput{Size: 2, Policy: OverflowDropNewest, Priority: true})
+	assert.NoError(t, err)
+
+	// the goroutine takes the first message and blocks writing it
+	err = l.Info("0")
+	assert.NoError(t, err)
+	<-bw.started
+
+	for i := 1; i < 5; i++ {
+		err := l.DebugF("debug %d", i)
+		assert.NoError(t, err)
+	}
+	err = l.Info("info")
+	assert.NoError(t, err)
+	for i := 1; i < 3; i++ {
+		err = l.ErrorF("error %d", i)
+		assert.NoError(t, err)
+	}
+
+	close(bw.gate)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"0", "error 1", "error 2", "info", "debug 1", "debug 2"}, readMessages(t, b.String()))
+	assert.Equal(t, map[string]uint64{"debug": 2}, l.Dropped())
+}
+
+func TestLoggerSetAsyncSharedWriter(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	// the same writer at two positions is wrapped by two async writers
+	l := NewLogger(map[string]int{"info": 0, "error": 1}, []Writer{w, w}, []string{"json", "json"}, false)
+	err := l.SetAsync(&AsyncInput{Size: 4})
+	assert.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+	for _, level := range []Level{LevelInfo, LevelError} {
+		wg.Add(1)
+		go func(level Level) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				err := l.Log(level, fmt.Sprint(i))
+				assert.NoError(t, err)
+			}
+		}(level)
+	}
+	wg.Wait()
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	assert.Len(t, readMessages(t, b.String()), 100)
+}
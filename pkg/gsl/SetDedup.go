@@ -0,0 +1,36 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"time"
+)
+
+// SetDedup turns on the suppression of duplicate messages using the given window or turns it off if the window is zero.
+// When a message has the same level, message, and fields, excluding the timestamp, as the previous message written by the logger
+// and is written within the window after the previous message, the message is suppressed.
+// The suppressed messages are summarized by a single message, e.g., "last message repeated 3812 times",
+// with the number of messages suppressed under the "repeated" field and the timestamps of the first and last messages suppressed
+// under the "repeated.first" and "repeated.last" fields.
+// The summary is written when a different message is written, when the window ends, or when the logger is closed or Fatal is called.
+//
+// Messages are compared across all the writers, so a message to one writer ends a repeat of a message to another writer.
+// Suppression is shared with child loggers, but the summaries are written using the field keys of this logger.
+func (l *Logger) SetDedup(window time.Duration) {
+	var d *deduper
+	if window > 0 {
+		d = newDeduper(l, window)
+	}
+	l.router.Lock()
+	previous := l.router.deduper
+	l.router.deduper = d
+	l.router.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+}
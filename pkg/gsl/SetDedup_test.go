@@ -0,0 +1,92 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+func TestLoggerSetDedup(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+	l.SetDedup(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		err := l.Errorw("connection refused", "host", "db")
+		assert.NoError(t, err)
+	}
+	err := l.Errorw("connection refused", "host", "cache")
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		err := l.Log(LevelInfo, "retrying", Int("attempt", 1))
+		assert.NoError(t, err)
+	}
+
+	l.Close()
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 5)
+
+	objects := make([]map[string]interface{}, 0, len(lines))
+	for _, line := range lines {
+		outObject := map[string]interface{}{}
+		err = json.Unmarshal([]byte(line), &outObject)
+		assert.NoError(t, err)
+		objects = append(objects, outObject)
+	}
+
+	assert.Equal(t, "db", objects[0]["host"])
+	assert.Equal(t, "error", objects[1]["level"])
+	assert.Equal(t, "last message repeated 4 times", objects[1]["msg"])
+	assert.Equal(t, float64(4), objects[1]["repeated"])
+	assert.NotEmpty(t, objects[1]["repeated.first"])
+	assert.NotEmpty(t, objects[1]["repeated.last"])
+	assert.Equal(t, "cache", objects[2]["host"])
+	assert.Equal(t, "retrying", objects[3]["msg"])
+	assert.Equal(t, "info", objects[4]["level"])
+	assert.Equal(t, "last message repeated 2 times", objects[4]["msg"])
+}
+
+func TestLoggerSetDedupFatal(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+	l.ExitFunc = func(code int) {}
+	l.SetDedup(time.Hour)
+	l.SetSampling(&SamplingInput{Interval: time.Hour, First: 1, Levels: []Level{LevelError}})
+	pinSamplerClock(l, 90*time.Second)
+
+	for i := 0; i < 3; i++ {
+		err := l.Info("retrying")
+		assert.NoError(t, err)
+		err = l.Error("failed")
+		assert.NoError(t, err)
+	}
+
+	// the pending repeat and the sampled counts are written before the fatal message
+	l.Fatal("exiting")
+
+	assert.Equal(t, []string{
+		"retrying",
+		"failed",
+		"retrying",
+		"last message repeated 1 time",
+		"sampled out 2 messages in the last 1m30s",
+		"exiting",
+	}, readMessages(t, b.String()))
+}
@@ -0,0 +1,31 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// SetSampling turns on sampling using the given configuration or turns it off if input is nil.
+// Messages are sampled by level and message, so the same message written many times in an interval is only written
+// for the first messages and then every Thereafter-th message.
+// At the end of each interval, a summary message is written for each level and message that was sampled out,
+// with the number of messages sampled out under the "sampled" field and the message under the "sampled.message" field.
+//
+// Close and Fatal stop sampling and write the summary for the current interval.
+// If sampling is already on, then the summary for the current interval is written before the new configuration is used.
+// Sampling is shared with child loggers, but the summaries are written using the field keys of this logger.
+func (l *Logger) SetSampling(input *SamplingInput) {
+	var s *sampler
+	if input != nil {
+		s = newSampler(l, *input)
+	}
+	l.router.Lock()
+	previous := l.router.sampler
+	l.router.sampler = s
+	l.router.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+}
@@ -0,0 +1,112 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+// pinSamplerClock replaces the clock of the sampler of the logger, so an interval ended early lasts the given time.
+func pinSamplerClock(l *Logger, elapsed time.Duration) {
+	s := l.router.sampler
+	s.clock = func() time.Time {
+		return s.start.Add(elapsed)
+	}
+}
+
+func TestLoggerSetSampling(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+	l.SetSampling(&SamplingInput{Interval: time.Hour, First: 2, Thereafter: 3, Levels: []Level{LevelInfo}})
+	pinSamplerClock(l, 90*time.Second)
+
+	for i := 0; i < 10; i++ {
+		err := l.Info(testMessage)
+		assert.NoError(t, err)
+		err = l.Error(testMessage)
+		assert.NoError(t, err)
+	}
+	err := l.Infow("other", "i", 1)
+	assert.NoError(t, err)
+
+	l.Close()
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 4+10+1+1)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal([]byte(lines[len(lines)-1]), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "info", outObject["level"])
+	assert.Equal(t, "sampled out 6 messages in the last 1m30s", outObject["msg"])
+	assert.Equal(t, float64(6), outObject["sampled"])
+	assert.Equal(t, testMessage, outObject["sampled.message"])
+}
+
+func TestLoggerSetSamplingInterval(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+	l.SetSampling(&SamplingInput{Interval: time.Hour, First: 1, Thereafter: 0})
+	pinSamplerClock(l, 90*time.Second)
+
+	// with Thereafter of zero, only the first messages in each interval are written
+	for i := 0; i < 5; i++ {
+		err := l.Info(testMessage)
+		assert.NoError(t, err)
+	}
+
+	// the counts are reset at the end of each interval, and an interval ended early by Close reports the time elapsed
+	l.router.sampler.summarize(true)
+
+	for i := 0; i < 5; i++ {
+		err := l.Info(testMessage)
+		assert.NoError(t, err)
+	}
+
+	l.Close()
+
+	assert.Equal(t, []string{
+		testMessage,
+		"sampled out 4 messages in the last 1h0m0s",
+		testMessage,
+		"sampled out 4 messages in the last 1m30s",
+	}, readMessages(t, b.String()))
+}
+
+func TestLoggerSetSamplingKeys(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+	l.SetSampling(&SamplingInput{Interval: time.Hour, First: 1, Keys: 2})
+	pinSamplerClock(l, 90*time.Second)
+
+	// messages past the number of keys are counted together
+	for i := 0; i < 5; i++ {
+		err := l.InfoF("%d", i)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, l.router.sampler.counts, 2)
+
+	l.Close()
+
+	assert.Equal(t, []string{"0", "1", "2", "sampled out 2 other messages in the last 1m30s"}, readMessages(t, b.String()))
+}
@@ -0,0 +1,166 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+//go:build go1.21
+// +build go1.21
+
+package gsl
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// SlogHandler is a slog.Handler that writes records through a gsl Logger,
+// so slog messages share the writers, formats, and level routing of the logger.
+type SlogHandler struct {
+	logger *Logger
+	attrs  map[string]interface{} // attributes added by WithAttrs, nested by group
+	groups []string               // groups opened by WithGroup
+}
+
+// NewSlogHandler returns a new slog.Handler that writes records through the given logger.
+//
+//	slogger := slog.New(gsl.NewSlogHandler(logger))
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger, attrs: map[string]interface{}{}}
+}
+
+// SlogLevel returns the gsl level for the given slog level.
+// Levels below slog.LevelDebug are mapped to LevelTrace.
+// Levels at or above slog.LevelError are mapped to LevelError, since writing a fatal message exits.
+func SlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	}
+	return LevelError
+}
+
+// Enabled returns true if the logger writes messages at the given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(SlogLevel(level))
+}
+
+// Handle writes the record through the logger.
+// Fields attached to the context with WithFields are included in the message.
+// The timestamp is taken from the record and, if the time of the record is zero, then the timestamp is omitted.
+// If AddCaller is true for the logger, then the caller is taken from the record.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	pinned := *h.logger.withContext(ctx)
+	l := &pinned
+	if r.Time.IsZero() {
+		l.TimeStampField = ""
+	} else {
+		l.timeStamp = r.Time
+	}
+	if l.AddCaller && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		l.caller = &frame
+	}
+	m := copyAttrs(h.attrs)
+	if r.NumAttrs() > 0 {
+		group := openGroups(m, h.groups)
+		r.Attrs(func(a slog.Attr) bool {
+			addAttr(group, a)
+			return true
+		})
+	}
+	if len(l.MessageField) > 0 {
+		m[l.MessageField] = r.Message
+	}
+	return l.write(SlogLevel(r.Level), record(m))
+}
+
+// WithAttrs returns a new handler that includes the given attributes in every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	m := copyAttrs(h.attrs)
+	group := openGroups(m, h.groups)
+	for _, a := range attrs {
+		addAttr(group, a)
+	}
+	return &SlogHandler{logger: h.logger, attrs: m, groups: h.groups}
+}
+
+// WithGroup returns a new handler that nests the attributes of every record under the given group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+	return &SlogHandler{logger: h.logger, attrs: h.attrs, groups: groups}
+}
+
+// copyAttrs returns a deep copy of the nested attributes.
+func copyAttrs(attrs map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		if group, ok := v.(map[string]interface{}); ok {
+			m[k] = copyAttrs(group)
+		} else {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// openGroups returns the map for the innermost group, creating the maps for the groups as needed.
+func openGroups(m map[string]interface{}, groups []string) map[string]interface{} {
+	for _, name := range groups {
+		group, ok := m[name].(map[string]interface{})
+		if !ok {
+			group = map[string]interface{}{}
+			m[name] = group
+		}
+		m = group
+	}
+	return m
+}
+
+// addAttr adds the resolved attribute to the map.
+// Empty attributes and empty groups are ignored and groups with empty keys are inlined as documented by slog.Handler.
+// Errors are written using their message.
+func addAttr(m map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		group := m
+		if len(a.Key) > 0 {
+			group = openGroups(m, []string{a.Key})
+		}
+		for _, ga := range attrs {
+			addAttr(group, ga)
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			m[a.Key] = err.Error()
+		} else {
+			m[a.Key] = a.Value.Any()
+		}
+	default:
+		m[a.Key] = a.Value.Any()
+	}
+}
@@ -0,0 +1,111 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+//go:build go1.21
+// +build go1.21
+
+package gsl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spatialcurrent/go-reader-writer/pkg/grw"
+)
+
+func TestSlogHandler(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0, "error": 0}, []Writer{w}, []string{"json"}, false)
+
+	slogger := slog.New(NewSlogHandler(l)).With("service", "api").WithGroup("request")
+
+	slogger.Debug("dropped")
+	slogger.Info("started", "id", "abc", slog.Group("client", "ip", "127.0.0.1"))
+
+	err := l.Flush()
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "info", outObject["level"])
+	assert.Equal(t, "started", outObject["msg"])
+	assert.Equal(t, "api", outObject["service"])
+	assert.Equal(t, map[string]interface{}{
+		"id":     "abc",
+		"client": map[string]interface{}{"ip": "127.0.0.1"},
+	}, outObject["request"])
+
+	b.Reset()
+
+	slogger.Error("failed", "err", fmt.Errorf("connection refused"))
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	outObject = map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "error", outObject["level"])
+	assert.Equal(t, map[string]interface{}{"err": "connection refused"}, outObject["request"])
+}
+
+func TestSlogHandlerTime(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"info": 0}, []Writer{w}, []string{"json"}, false)
+	l.TimeStampFormat = time.RFC3339
+
+	r := slog.NewRecord(time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, testMessage, 0)
+	err := NewSlogHandler(l).Handle(context.Background(), r)
+	assert.NoError(t, err)
+
+	err = l.Flush()
+	assert.NoError(t, err)
+
+	outObject := map[string]interface{}{}
+	err = json.Unmarshal(b.Bytes(), &outObject)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2019-01-02T03:04:05Z", outObject["ts"])
+}
+
+func TestSlogHandlerConformance(t *testing.T) {
+
+	w, b := grw.WriteMemoryBytes()
+
+	l := NewLogger(map[string]int{"debug": 0, "info": 0, "warn": 0, "error": 0}, []Writer{w}, []string{"json"}, true)
+	l.TimeStampField = slog.TimeKey
+	l.LevelField = slog.LevelKey
+	l.MessageField = slog.MessageKey
+
+	err := slogtest.TestHandler(NewSlogHandler(l), func() []map[string]interface{} {
+		results := make([]map[string]interface{}, 0)
+		for _, line := range bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n")) {
+			m := map[string]interface{}{}
+			if err := json.Unmarshal(line, &m); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, m)
+		}
+		return results
+	})
+	assert.NoError(t, err)
+}
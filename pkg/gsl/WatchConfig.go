@@ -0,0 +1,138 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultWatchInterval is the interval used by WatchConfig to poll the configuration file if no interval is given.
+const DefaultWatchInterval = 5 * time.Second
+
+// ConfigWatcher polls a configuration file and applies the configuration to its logger whenever the file changes.
+type ConfigWatcher struct {
+	logger   *Logger
+	path     string
+	interval time.Duration
+	mutex    sync.Mutex
+	last     []byte        // contents of the file when last read
+	done     chan struct{} // closed when the watcher is stopped
+	once     sync.Once
+}
+
+// WatchConfig loads the configuration file at the given path, creates a logger from it, and starts polling the file for changes at the given interval.
+// If the interval is not positive, then DefaultWatchInterval is used.
+// When the file changes, the new writers, formats, and level routing are applied atomically using ApplyConfig.
+// If the changed configuration is invalid, then the error is written to the error writer and the previous configuration is kept.
+// Call Stop to stop polling the file.
+func WatchConfig(path string, interval time.Duration) (*ConfigWatcher, error) {
+	b, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error reading config from %s", path))
+	}
+	c, err := parseConfigFile(path, b)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := NewLoggerFromConfig(c)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error creating logger from config at %s", path))
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	w := &ConfigWatcher{
+		logger:   logger,
+		path:     path,
+		interval: interval,
+		last:     b,
+		done:     make(chan struct{}),
+	}
+	go w.poll()
+	return w, nil
+}
+
+// Logger returns the logger managed by the watcher.
+func (w *ConfigWatcher) Logger() *Logger {
+	return w.logger
+}
+
+// Reload reads the configuration file and applies it to the logger if the file has changed since it was last read.
+// Reload is called automatically at every interval, but can be called directly, e.g., on SIGHUP.
+func (w *ConfigWatcher) Reload() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	b, err := ioutil.ReadFile(w.path) // #nosec
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error reading config from %s", w.path))
+	}
+	if bytes.Equal(b, w.last) {
+		return nil
+	}
+	c, err := parseConfigFile(w.path, b)
+	if err != nil {
+		return err
+	}
+	err = w.logger.ApplyConfig(c)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error applying config from %s", w.path))
+	}
+	w.last = b
+	return nil
+}
+
+// Stop stops polling the configuration file.
+// The logger is not closed.
+func (w *ConfigWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+// poll reloads the configuration at every interval until the watcher is stopped.
+// The same error is only written once, so a broken configuration file does not flood the error writer.
+func (w *ConfigWatcher) poll() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	lastError := ""
+	for {
+		select {
+		case <-ticker.C:
+			err := w.Reload()
+			if err != nil {
+				if err.Error() != lastError {
+					w.logger.Error(err) // #nosec
+					lastError = err.Error()
+				}
+			} else {
+				lastError = ""
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// parseConfigFile parses the given contents of the configuration file at the given path.
+func parseConfigFile(path string, b []byte) (*Config, error) {
+	format, err := configFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseConfig(b, format)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error parsing config from %s", path))
+	}
+	return c, nil
+}
@@ -0,0 +1,68 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gsl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // #nosec
+
+	path := filepath.Join(dir, "logger.json")
+	a := filepath.Join(dir, "a.log")
+	b := filepath.Join(dir, "b.log")
+
+	config := `{"writers": [{"name": "main", "destination": %q, "format": "json"}], "levels": {"info": "main", "error": "main"}}`
+
+	err = ioutil.WriteFile(path, []byte(fmt.Sprintf(config, a)), 0600)
+	assert.NoError(t, err)
+
+	// the interval is long, so the test reloads the configuration directly
+	w, err := WatchConfig(path, time.Hour)
+	assert.NoError(t, err)
+	defer w.Stop()
+
+	l := w.Logger().With(map[string]interface{}{"job": "test"})
+
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+
+	err = ioutil.WriteFile(path, []byte(fmt.Sprintf(config, b)), 0600)
+	assert.NoError(t, err)
+
+	err = w.Reload()
+	assert.NoError(t, err)
+
+	// reloading an unchanged file does nothing
+	err = w.Reload()
+	assert.NoError(t, err)
+
+	err = l.Info(testMessage)
+	assert.NoError(t, err)
+
+	aBytes, err := ioutil.ReadFile(a)
+	assert.NoError(t, err)
+	assert.Contains(t, string(aBytes), testMessage)
+
+	w.Logger().Close()
+
+	bBytes, err := ioutil.ReadFile(b)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bBytes), testMessage)
+}
@@ -0,0 +1,217 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"sync"
+)
+
+// asyncWriter is a Writer that queues formatted messages in a bounded queue
+// and writes them to the underlying writer from a background goroutine.
+// The goroutine flushes the underlying writer after writing the messages in the queue.
+//
+// Lock and Unlock exclude the goroutine, so WriteLine, Flush, and Close drain the queue
+// and write to the underlying writer directly, preserving the order of messages.
+// The same writer can be used at multiple positions, each with its own asyncWriter,
+// so the lock of the underlying writer is also held around each write and flush.
+type asyncWriter struct {
+	writer    Writer     // the underlying writer
+	lock      sync.Mutex // held while writing to the underlying writer
+	mutex     sync.Mutex // guards queue and closed
+	notEmpty  *sync.Cond // signaled when an entry is queued or the writer is closed
+	notFull   *sync.Cond // signaled when entries are removed or the writer is closed
+	queue     entryQueue // the queued entries
+	closed    bool       // the goroutine has stopped and new messages are written synchronously
+	policy    OverflowPolicy
+	dropLevel Level
+	dropped   *dropCounter
+}
+
+// newAsyncWriter returns a new asyncWriter for the underlying writer and starts its goroutine.
+func newAsyncWriter(writer Writer, input *AsyncInput, dropped *dropCounter) *asyncWriter {
+	size := input.Size
+	if size <= 0 {
+		size = DefaultAsyncSize
+	}
+	var queue entryQueue = newRingBuffer(size)
+	if input.Priority {
+		queue = newPriorityQueue(size)
+	}
+	a := &asyncWriter{
+		writer:    writer,
+		queue:     queue,
+		policy:    input.Policy,
+		dropLevel: input.DropLevel,
+		dropped:   dropped,
+	}
+	a.notEmpty = sync.NewCond(&a.mutex)
+	a.notFull = sync.NewCond(&a.mutex)
+	go a.run()
+	return a
+}
+
+// run writes queued entries to the underlying writer until the writer is stopped.
+func (a *asyncWriter) run() {
+	for {
+		a.mutex.Lock()
+		for !a.closed && a.queue.len() == 0 {
+			a.notEmpty.Wait()
+		}
+		a.mutex.Unlock()
+		a.lock.Lock()
+		if a.stopped() {
+			a.lock.Unlock()
+			return
+		}
+		a.drain()            // #nosec
+		a.writer.FlushSafe() // #nosec
+		a.lock.Unlock()
+	}
+}
+
+// stopped returns true if the writer has been stopped.
+func (a *asyncWriter) stopped() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.closed
+}
+
+// enqueue adds the formatted message to the queue, applying the overflow policy if the queue is full.
+// If the writer is stopped, then the message is written synchronously.
+func (a *asyncWriter) enqueue(level Level, line string) error {
+	a.mutex.Lock()
+	for !a.closed && a.queue.full(level) {
+		switch a.policy {
+		case OverflowDropNewest:
+			a.mutex.Unlock()
+			a.dropped.add(level)
+			return nil
+		case OverflowDropOldest:
+			a.dropped.add(a.queue.dropOldest(level).level)
+		case OverflowDropBelow:
+			if level < a.dropLevel {
+				a.mutex.Unlock()
+				a.dropped.add(level)
+				return nil
+			}
+			a.notFull.Wait()
+		default:
+			a.notFull.Wait()
+		}
+	}
+	if a.closed {
+		a.mutex.Unlock()
+		_, err := a.WriteLineSafe(line)
+		return err
+	}
+	a.queue.push(entry{level: level, line: line})
+	a.notEmpty.Signal()
+	a.mutex.Unlock()
+	return nil
+}
+
+// drain writes the entries in the queue to the underlying writer.
+// Entries are removed one at a time, so entries queued during the drain at a more severe level are written first by a priority queue.
+// To bound the time spent draining, drain writes as many entries as were in the queue when it started.
+// The caller must hold the lock.
+// If an entry cannot be written, then the remaining entries are still written and the first error is returned.
+func (a *asyncWriter) drain() error {
+	a.mutex.Lock()
+	n := a.queue.len()
+	a.mutex.Unlock()
+	var first error
+	for i := 0; i < n; i++ {
+		a.mutex.Lock()
+		if a.queue.len() == 0 {
+			a.mutex.Unlock()
+			break
+		}
+		e := a.queue.pop()
+		a.notFull.Broadcast()
+		a.mutex.Unlock()
+		if _, err := a.writeLine(e.line); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// stop drains the queue and stops the goroutine, leaving the underlying writer open.
+// Messages written after stop are written synchronously.
+// The caller must hold the lock.
+func (a *asyncWriter) stop() error {
+	a.mutex.Lock()
+	a.closed = true
+	a.notEmpty.Broadcast()
+	a.notFull.Broadcast()
+	a.mutex.Unlock()
+	return a.drain()
+}
+
+// WriteLine drains the queue and then writes the line to the underlying writer.
+// The caller must hold the lock.
+func (a *asyncWriter) WriteLine(str string) (int, error) {
+	if err := a.drain(); err != nil {
+		return 0, err
+	}
+	return a.writeLine(str)
+}
+
+// writeLine locks the underlying writer, writes the line, and then unlocks.
+func (a *asyncWriter) writeLine(str string) (int, error) {
+	a.writer.Lock()
+	defer a.writer.Unlock()
+	return a.writer.WriteLine(str)
+}
+
+// WriteLineSafe locks the writer, drains the queue, writes the line to the underlying writer, and then unlocks.
+// The logger queues messages with enqueue, so WriteLineSafe is only used when the writer is called directly.
+func (a *asyncWriter) WriteLineSafe(str string) (int, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.WriteLine(str)
+}
+
+// Lock locks the writer, which excludes the goroutine.
+func (a *asyncWriter) Lock() {
+	a.lock.Lock()
+}
+
+// Unlock unlocks the writer.
+func (a *asyncWriter) Unlock() {
+	a.lock.Unlock()
+}
+
+// Flush drains the queue and then flushes the underlying writer.
+// The caller must hold the lock.
+func (a *asyncWriter) Flush() error {
+	if err := a.drain(); err != nil {
+		return err
+	}
+	return a.writer.FlushSafe()
+}
+
+// FlushSafe locks the writer, drains the queue, flushes the underlying writer, and then unlocks.
+func (a *asyncWriter) FlushSafe() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.Flush()
+}
+
+// Close stops the goroutine, drains the queue, and then closes the underlying writer.
+// The caller must hold the lock.
+func (a *asyncWriter) Close() error {
+	err := a.stop()
+	a.writer.Lock()
+	defer a.writer.Unlock()
+	if err != nil {
+		a.writer.Close() // #nosec
+		return err
+	}
+	return a.writer.Close()
+}
@@ -0,0 +1,102 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// gslPackage is the import path of this package, used to skip its frames when finding the caller.
+var gslPackage = packageName(runtime.FuncForPC(reflect.ValueOf(isFileURI).Pointer()).Name())
+
+// packageName returns the import path of the package from the fully qualified name of a function,
+// e.g., "github.com/spatialcurrent/go-sync-logger/pkg/gsl" from "github.com/spatialcurrent/go-sync-logger/pkg/gsl.(*Logger).Info".
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// isInternalFrame returns true if the frame is within this package, excluding tests, within the runtime,
+// or within the standard library log package used by StdLogger.
+func isInternalFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "runtime.") || strings.HasPrefix(frame.Function, "log.") {
+		return true
+	}
+	return strings.HasPrefix(frame.Function, gslPackage+".") && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// callerFrame returns the frame of the code that called the logger.
+// The frames within this package are skipped, followed by CallerSkip more frames, e.g., for wrappers around the logger.
+// If the logger is pinned to a caller by a Listen method, then returns that caller.
+// If no frame is found, e.g., when a Listen method is started directly with the go statement, then returns false.
+func (l *Logger) callerFrame() (runtime.Frame, bool) {
+	if l.caller != nil {
+		return *l.caller, true
+	}
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	skip := l.CallerSkip
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame) {
+			if skip == 0 {
+				return frame, true
+			}
+			skip--
+		}
+		if !more {
+			break
+		}
+	}
+	return runtime.Frame{}, false
+}
+
+// withCaller returns a child logger with the caller and, optionally, function fields bound.
+// If the caller cannot be found, then returns the logger itself.
+func (l *Logger) withCaller() *Logger {
+	frame, ok := l.callerFrame()
+	if !ok {
+		return l
+	}
+	fields := map[string]interface{}{}
+	if len(l.CallerField) > 0 {
+		file := frame.File
+		if !l.FullCallerPath {
+			file = filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+		}
+		fields[l.CallerField] = fmt.Sprintf("%s:%d", filepath.ToSlash(file), frame.Line)
+	}
+	if l.AddFunc && len(l.FuncField) > 0 {
+		fields[l.FuncField] = frame.Function
+	}
+	return l.With(fields)
+}
+
+// pinCaller returns a copy of the logger that uses the current caller for every message.
+// The Listen methods use pinCaller, since messages received from a channel have no caller within the goroutine.
+// If caller annotation is off or the caller cannot be found, then returns the logger itself.
+func (l *Logger) pinCaller() *Logger {
+	if !l.AddCaller {
+		return l
+	}
+	frame, ok := l.callerFrame()
+	if !ok {
+		return l
+	}
+	pinned := *l
+	pinned.caller = &frame
+	return &pinned
+}
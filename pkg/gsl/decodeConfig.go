@@ -0,0 +1,163 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decodeConfig decodes a configuration from a deserialized object.
+// If a value has the wrong type, then returns an ErrInvalidConfig error that points to the value.
+func decodeConfig(obj interface{}) (*Config, error) {
+	m, err := decodeMap("", obj)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	for key, value := range m {
+		switch key {
+		case "writers":
+			items, err := decodeSlice(key, value)
+			if err != nil {
+				return nil, err
+			}
+			c.Writers = make([]WriterConfig, 0, len(items))
+			for i, item := range items {
+				w, err := decodeWriterConfig(fmt.Sprintf("writers[%d]", i), item)
+				if err != nil {
+					return nil, err
+				}
+				c.Writers = append(c.Writers, *w)
+			}
+		case "levels":
+			levels, err := decodeMap(key, value)
+			if err != nil {
+				return nil, err
+			}
+			c.Levels = make(map[string]string, len(levels))
+			for level, name := range levels {
+				str, err := decodeString(key+"."+level, name)
+				if err != nil {
+					return nil, err
+				}
+				c.Levels[level] = str
+			}
+		case "min-level":
+			c.MinLevel, err = decodeString(key, value)
+		case "level-field":
+			c.LevelField, err = decodeString(key, value)
+		case "timestamp-field":
+			c.TimeStampField, err = decodeString(key, value)
+		case "timestamp-format":
+			c.TimeStampFormat, err = decodeString(key, value)
+		case "message-field":
+			c.MessageField, err = decodeString(key, value)
+		case "auto-flush":
+			c.AutoFlush, err = decodeBool(key, value)
+		default:
+			err = &ErrInvalidConfig{Path: key, Err: fmt.Errorf("unknown key %q", key)}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// decodeWriterConfig decodes the configuration for a writer at the given path.
+func decodeWriterConfig(path string, obj interface{}) (*WriterConfig, error) {
+	m, err := decodeMap(path, obj)
+	if err != nil {
+		return nil, err
+	}
+	w := &WriterConfig{}
+	for key, value := range m {
+		switch key {
+		case "name":
+			w.Name, err = decodeString(path+"."+key, value)
+		case "destination":
+			w.Destination, err = decodeString(path+"."+key, value)
+		case "format":
+			w.Format, err = decodeString(path+"."+key, value)
+		case "compression":
+			w.Compression, err = decodeString(path+"."+key, value)
+		case "append":
+			w.Append, err = decodeBool(path+"."+key, value)
+		default:
+			err = &ErrInvalidConfig{Path: path + "." + key, Err: fmt.Errorf("unknown key %q", key)}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// decodeMap decodes a map with string keys at the given path.
+// Maps with interface{} keys, as returned by some YAML decoders, are converted.
+func decodeMap(path string, obj interface{}) (map[string]interface{}, error) {
+	if m, ok := obj.(map[string]interface{}); ok {
+		return m, nil
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Map {
+		return nil, &ErrInvalidConfig{Path: configPath(path), Err: fmt.Errorf("expecting a map, found %T", obj)}
+	}
+	m := make(map[string]interface{}, v.Len())
+	for _, k := range v.MapKeys() {
+		key, ok := k.Interface().(string)
+		if !ok {
+			return nil, &ErrInvalidConfig{Path: configPath(path), Err: fmt.Errorf("expecting string keys, found %T", k.Interface())}
+		}
+		m[key] = v.MapIndex(k).Interface()
+	}
+	return m, nil
+}
+
+// decodeSlice decodes a slice at the given path.
+func decodeSlice(path string, obj interface{}) ([]interface{}, error) {
+	if s, ok := obj.([]interface{}); ok {
+		return s, nil
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, &ErrInvalidConfig{Path: path, Err: fmt.Errorf("expecting a list, found %T", obj)}
+	}
+	s := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		s = append(s, v.Index(i).Interface())
+	}
+	return s, nil
+}
+
+// decodeString decodes a string at the given path.
+func decodeString(path string, obj interface{}) (string, error) {
+	str, ok := obj.(string)
+	if !ok {
+		return "", &ErrInvalidConfig{Path: path, Err: fmt.Errorf("expecting a string, found %T", obj)}
+	}
+	return str, nil
+}
+
+// decodeBool decodes a boolean at the given path.
+func decodeBool(path string, obj interface{}) (*bool, error) {
+	b, ok := obj.(bool)
+	if !ok {
+		return nil, &ErrInvalidConfig{Path: path, Err: fmt.Errorf("expecting a boolean, found %T", obj)}
+	}
+	return &b, nil
+}
+
+// configPath returns the path to use in errors, using "." for the root of the configuration.
+func configPath(path string) string {
+	if len(path) == 0 {
+		return "."
+	}
+	return path
+}
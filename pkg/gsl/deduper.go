@@ -0,0 +1,124 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// repeat is the consecutive copies of a record that were suppressed as duplicates.
+type repeat struct {
+	level Level     // the level of the record
+	count int       // the number of copies suppressed
+	first time.Time // the time of the first copy suppressed
+	last  time.Time // the time of the last copy suppressed
+}
+
+// deduper suppresses consecutive copies of a record within a window of the first copy written.
+// The suppressed copies are summarized when a different record is written, when the window ends, or when the deduper is stopped.
+// The deduper only keeps the signature of the last record, so it does not keep references to objects passed in by callers or pooled maps.
+// The deduper is shared by a logger and its children.
+type deduper struct {
+	sync.Mutex
+	logger    *Logger       // the logger that writes the summaries
+	window    time.Duration // the length of the window after a record is written
+	level     Level         // the level of the last record written
+	signature uint64        // the signature of the last record written
+	start     time.Time     // the time the last record was written
+	pending   *repeat       // the suppressed copies of the last record, if any
+	timer     *time.Timer   // summarizes the suppressed copies at the end of the window
+	gen       int           // incremented for each repeat, so a stale timer does nothing
+}
+
+// newDeduper returns a new deduper that writes summaries using the given logger.
+func newDeduper(logger *Logger, window time.Duration) *deduper {
+	return &deduper{logger: logger, window: window}
+}
+
+// check returns true if the record with the given level and signature should be written.
+// If the record ends a repeat, then also returns the repeat, which should be summarized before the record is written.
+func (d *deduper) check(level Level, signature uint64, now time.Time) (bool, *repeat) {
+	d.Lock()
+	defer d.Unlock()
+	if level == d.level && signature == d.signature && now.Sub(d.start) < d.window {
+		if d.pending == nil {
+			d.pending = &repeat{level: level, first: now}
+			d.gen++
+			gen := d.gen
+			d.timer = time.AfterFunc(d.start.Add(d.window).Sub(now), func() {
+				d.expire(gen)
+			})
+		}
+		d.pending.count++
+		d.pending.last = now
+		return false, nil
+	}
+	repeated := d.take()
+	d.level = level
+	d.signature = signature
+	d.start = now
+	return true, repeated
+}
+
+// take removes and returns the pending repeat, if any.
+// The caller must hold the lock.
+func (d *deduper) take() *repeat {
+	r := d.pending
+	d.pending = nil
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	return r
+}
+
+// expire writes the summary of the repeat at the end of the window, unless the repeat has already been summarized.
+func (d *deduper) expire(gen int) {
+	d.Lock()
+	if gen != d.gen {
+		d.Unlock()
+		return
+	}
+	r := d.take()
+	d.Unlock()
+	if r != nil {
+		d.logger.output(r.level, d.summary(r), false) // #nosec
+	}
+}
+
+// stop writes the summary of the pending repeat, if any.
+// The caller must not hold the router lock.
+func (d *deduper) stop() {
+	d.Lock()
+	r := d.take()
+	d.Unlock()
+	if r != nil {
+		d.logger.output(r.level, d.summary(r), false) // #nosec
+	}
+}
+
+// summary returns the record summarizing the repeat, e.g., "last message repeated 3 times",
+// with the number of copies suppressed and the timestamps of the first and last copies.
+func (d *deduper) summary(r *repeat) record {
+	l := d.logger
+	m := record{
+		"repeated":       r.count,
+		"repeated.first": r.first.Format(l.TimeStampFormat),
+		"repeated.last":  r.last.Format(l.TimeStampFormat),
+	}
+	if len(l.MessageField) > 0 {
+		if r.count == 1 {
+			m[l.MessageField] = "last message repeated 1 time"
+		} else {
+			m[l.MessageField] = fmt.Sprintf("last message repeated %d times", r.count)
+		}
+	}
+	return m
+}
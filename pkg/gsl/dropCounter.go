@@ -0,0 +1,36 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"sync/atomic"
+)
+
+// dropCounter counts the messages dropped in async mode by level.
+// The counter is shared by all the writers of a logger and is safe for concurrent use.
+type dropCounter struct {
+	counts [LevelFatal + 1]uint64
+}
+
+// add counts a dropped message at the given level.
+func (d *dropCounter) add(level Level) {
+	if level >= 0 && int(level) < len(d.counts) {
+		atomic.AddUint64(&d.counts[level], 1)
+	}
+}
+
+// snapshot returns the number of dropped messages by level name, omitting levels with no dropped messages.
+func (d *dropCounter) snapshot() map[string]uint64 {
+	m := map[string]uint64{}
+	for _, level := range Levels {
+		if n := atomic.LoadUint64(&d.counts[level]); n > 0 {
+			m[level.String()] = n
+		}
+	}
+	return m
+}
@@ -0,0 +1,18 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// entryQueue is a bounded queue of entries waiting to be written by an asyncWriter.
+// An entryQueue is not safe for concurrent use.
+type entryQueue interface {
+	len() int                     // the number of entries in the queue
+	full(level Level) bool        // the queue cannot hold another entry at the level
+	push(e entry)                 // add the entry, the caller must check that the queue is not full
+	pop() entry                   // remove the next entry to write, the caller must check that the queue is not empty
+	dropOldest(level Level) entry // remove the oldest entry to make space for an entry at the level
+}
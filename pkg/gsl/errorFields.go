@@ -0,0 +1,96 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxErrorChain is the maximum number of errors walked in a chain, which bounds the walk for chains that wrap themselves.
+const maxErrorChain = 100
+
+// stackTracer is implemented by errors created by github.com/pkg/errors.
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// unwrap returns the error wrapped by the given error, supporting both github.com/pkg/errors and the standard library.
+// If the error does not wrap another error, then returns nil.
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+	return nil
+}
+
+// addErrorFields adds the type, cause chain, and stack trace of the error to the record under the error field.
+// The chain contains the message added by each wrapped error, from the outermost to the root cause.
+// The stack trace is taken from the deepest error that has one and is only added for the formats in ErrorStackFormats.
+// Past ErrorChainDepth, the chain is only walked to find the stack trace, so the type is of the deepest error walked.
+// At most maxErrorChain errors are walked, so errors that wrap themselves do not loop forever.
+func (l *Logger) addErrorFields(m map[string]interface{}, err error, format string) {
+	if len(l.ErrorField) == 0 {
+		return
+	}
+
+	includeStack := l.includeStack(format)
+	chain := make([]string, 0)
+	var stack errors.StackTrace
+	root := err
+	for i, e := 0, err; e != nil && i < maxErrorChain; i, e = i+1, unwrap(e) {
+		root = e
+		if st, ok := e.(stackTracer); ok {
+			stack = st.StackTrace()
+		}
+		if l.ErrorChainDepth > 0 && len(chain) >= l.ErrorChainDepth {
+			if !includeStack {
+				break
+			}
+			continue
+		}
+		msg := e.Error()
+		if next := unwrap(e); next != nil {
+			nextMsg := next.Error()
+			if msg == nextMsg {
+				continue // the error only adds a stack trace
+			}
+			msg = strings.TrimSuffix(msg, ": "+nextMsg)
+		}
+		chain = append(chain, msg)
+	}
+
+	m[l.ErrorField+".type"] = fmt.Sprintf("%T", root)
+	m[l.ErrorField+".chain"] = chain
+
+	if len(stack) > 0 && includeStack {
+		if l.ErrorStackDepth > 0 && len(stack) > l.ErrorStackDepth {
+			stack = stack[:l.ErrorStackDepth]
+		}
+		frames := make([]string, 0, len(stack))
+		for _, f := range stack {
+			frames = append(frames, strings.Replace(fmt.Sprintf("%+v", f), "\n\t", " ", -1))
+		}
+		m[l.ErrorField+".stack"] = frames
+	}
+}
+
+// includeStack returns true if stack traces are written for the given format.
+func (l *Logger) includeStack(format string) bool {
+	for _, f := range l.ErrorStackFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// fieldRecordPool is a pool of field records used by Log to avoid allocating a record per call.
+var fieldRecordPool = sync.Pool{
+	New: func() interface{} {
+		return &fieldRecord{}
+	},
+}
+
+// fieldRecord is a message with typed fields written by Log.
+// The fields are kept typed, so for the json format they are encoded without boxing their values into a map.
+type fieldRecord struct {
+	msg    string
+	fields []Field
+	keys   []fieldKey // scratch space reused by appendJSON
+}
+
+// fieldKey is a key written by appendJSON and the source of its value.
+type fieldKey struct {
+	key   string
+	index int // the index of the field, or one of the fieldKey constants below
+}
+
+const (
+	fieldKeyMessage   = -1
+	fieldKeyLevel     = -2
+	fieldKeyTimeStamp = -3
+	fieldKeyBound     = -4
+)
+
+// record returns the fields as a record, boxing the values of the fields.
+func (r *fieldRecord) record(messageField string) record {
+	m := make(map[string]interface{}, len(r.fields)+1)
+	for _, f := range r.fields {
+		if f.Type != SkipType {
+			m[f.Key] = f.Value()
+		}
+	}
+	if len(messageField) > 0 {
+		m[messageField] = r.msg
+	}
+	return record(m)
+}
+
+// hasField returns true if a field is written with the given key.
+func (r *fieldRecord) hasField(key string) bool {
+	for _, f := range r.fields {
+		if f.Type != SkipType && f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// appendJSON appends the record formatted as a json object with sorted keys to b,
+// which is the same as the record formatted through a map.
+// If the record cannot be formatted directly, e.g., when keys collide or a float is not finite,
+// then returns false and the record must be formatted through a map.
+func (r *fieldRecord) appendJSON(b []byte, l *Logger, level string) ([]byte, bool) {
+	keys := r.keys[:0]
+	for i, f := range r.fields {
+		if f.Type == SkipType {
+			continue
+		}
+		if f.Key == l.LevelField || f.Key == l.TimeStampField {
+			return b, false
+		}
+		keys = append(keys, fieldKey{key: f.Key, index: i})
+	}
+	if len(l.MessageField) > 0 {
+		keys = append(keys, fieldKey{key: l.MessageField, index: fieldKeyMessage})
+	}
+	for k := range l.fields {
+		if k != l.MessageField && k != l.LevelField && k != l.TimeStampField && !r.hasField(k) {
+			keys = append(keys, fieldKey{key: k, index: fieldKeyBound})
+		}
+	}
+	if len(l.LevelField) > 0 {
+		keys = append(keys, fieldKey{key: l.LevelField, index: fieldKeyLevel})
+	}
+	if len(l.TimeStampField) > 0 {
+		keys = append(keys, fieldKey{key: l.TimeStampField, index: fieldKeyTimeStamp})
+	}
+	r.keys = keys
+	// insertion sort, since records have few keys and sort.Slice allocates
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j].key < keys[j-1].key; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+	b = append(b, '{')
+	for i, k := range keys {
+		if i > 0 {
+			if k.key == keys[i-1].key {
+				return b, false
+			}
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, k.key)
+		b = append(b, ':')
+		var ok bool
+		switch k.index {
+		case fieldKeyMessage:
+			b, ok = appendJSONString(b, r.msg), true
+		case fieldKeyLevel:
+			b, ok = appendJSONString(b, level), true
+		case fieldKeyTimeStamp:
+			// the values in a timestamp never need escaping, so only the layout is checked
+			if !isJSONSafe(l.TimeStampFormat) {
+				return b, false
+			}
+			b = append(b, '"')
+			b = l.now().AppendFormat(b, l.TimeStampFormat)
+			b, ok = append(b, '"'), true
+		case fieldKeyBound:
+			b, ok = appendJSONValue(b, l.fields[k.key])
+		default:
+			b, ok = appendJSONField(b, r.fields[k.index])
+		}
+		if !ok {
+			return b, false
+		}
+	}
+	return append(b, '}'), true
+}
+
+// appendJSONField appends the value of the field formatted as json to b.
+func appendJSONField(b []byte, f Field) ([]byte, bool) {
+	switch f.Type {
+	case StringType:
+		return appendJSONString(b, f.String), true
+	case Int64Type, DurationType:
+		return strconv.AppendInt(b, f.Integer, 10), true
+	case Float64Type:
+		return appendJSONFloat(b, math.Float64frombits(uint64(f.Integer)))
+	case BoolType:
+		return strconv.AppendBool(b, f.Integer == 1), true
+	}
+	return appendJSONValue(b, f.Interface)
+}
+
+// appendJSONValue appends the value formatted by encoding/json to b.
+func appendJSONValue(b []byte, v interface{}) ([]byte, bool) {
+	if s, ok := v.(string); ok {
+		return appendJSONString(b, s), true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return b, false
+	}
+	return append(b, data...), true
+}
+
+// appendJSONFloat appends the float formatted as encoding/json formats floats to b.
+// Returns false if the float is not finite, since encoding/json cannot format it.
+func appendJSONFloat(b []byte, f float64) ([]byte, bool) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return b, false
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, true
+}
+
+// appendJSONString appends the string quoted as encoding/json quotes strings to b.
+// Strings that need escaping are rare in logs, so they are quoted by encoding/json.
+func appendJSONString(b []byte, s string) []byte {
+	if !isJSONSafe(s) {
+		data, _ := json.Marshal(s) // #nosec
+		return append(b, data...)
+	}
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
+
+// isJSONSafe returns true if encoding/json writes the string without escaping any characters.
+func isJSONSafe(s string) bool {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+				return false
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || r == '\u2028' || r == '\u2029' {
+			return false
+		}
+		i += size
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// BadKeyField is the key for the values passed to a key-value logging method, e.g., Infow, that are not part of a valid key-value pair.
+const BadKeyField = "!BADKEY"
+
+// keysAndValues returns a record containing the message and the given alternating keys and values.
+// Keys must be strings.  A value in a key position that is not a string, including a trailing key without a value, is added to the BadKeyField list and the pairing continues with the next value.
+// The message takes precedence over a key with the same name as the message field.
+func (l *Logger) keysAndValues(msg string, keysAndValues []interface{}) record {
+	m := make(record, len(keysAndValues)/2+3)
+	bad := make([]interface{}, 0)
+	for i := 0; i < len(keysAndValues); {
+		key, ok := keysAndValues[i].(string)
+		if !ok || i+1 == len(keysAndValues) {
+			bad = append(bad, keysAndValues[i])
+			i++
+			continue
+		}
+		m[key] = keysAndValues[i+1]
+		i += 2
+	}
+	if len(bad) > 0 {
+		m[BadKeyField] = bad
+	}
+	if len(l.MessageField) > 0 {
+		m[l.MessageField] = msg
+	}
+	return m
+}
@@ -0,0 +1,53 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"time"
+)
+
+// limitRule decides whether a message written through a view returned by Once, Every, or Throttle is written.
+// Rules are guarded by the lock of the registry.
+type limitRule struct {
+	key      limitKey      // the key of the rule in the registry
+	once     bool          // write only the first message
+	every    int           // write every nth message, or zero if the rule uses an interval
+	interval time.Duration // write at most one message per interval
+	count    int           // the number of messages seen
+	last     time.Time     // the time of the last message written
+}
+
+// allow returns true if the message should be written and counts the message.
+// The caller must hold the lock of the registry.
+func (r *limitRule) allow(now time.Time) bool {
+	if r.once {
+		if r.count > 0 {
+			return false
+		}
+		r.count = 1
+		return true
+	}
+	if r.every > 0 {
+		r.count++
+		return (r.count-1)%r.every == 0
+	}
+	if r.expired(now) {
+		r.last = now
+		return true
+	}
+	return false
+}
+
+// expired returns true if the rule is a throttle rule whose interval has passed,
+// so the rule behaves the same as a new rule and can be evicted.
+func (r *limitRule) expired(now time.Time) bool {
+	if r.once || r.every > 0 {
+		return false
+	}
+	return r.last.IsZero() || now.Sub(r.last) >= r.interval
+}
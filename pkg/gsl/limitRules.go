@@ -0,0 +1,93 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"sync"
+	"time"
+)
+
+// limitKey identifies a rule by the method that created it, the key passed by the caller, and the parameters of the rule,
+// so calls with the same key and different parameters use different rules.
+type limitKey struct {
+	kind     string
+	key      string
+	every    int
+	interval time.Duration
+}
+
+// minLimitSweep is the minimum number of rules in the registry before expired rules are evicted.
+const minLimitSweep = 64
+
+// limitRules is the registry of the rules used by Once, Every, and Throttle.
+// The registry is shared by a logger and its children, so views with the same key share a rule.
+// Throttle rules whose interval has passed are evicted once the number of rules doubles,
+// while Once and Every rules are kept for the life of the logger.
+type limitRules struct {
+	sync.Mutex
+	rules map[limitKey]*limitRule
+	clock func() time.Time // returns the current time, defaults to time.Now
+	sweep int              // the number of rules after the last eviction
+}
+
+// now returns the current time from the clock.
+func (r *limitRules) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+	return time.Now()
+}
+
+// get returns the rule for the given kind, key, every, and interval, creating the rule if it does not exist.
+// Rules of the "once" kind only write the first message.
+func (r *limitRules) get(kind string, key string, every int, interval time.Duration) *limitRule {
+	r.Lock()
+	defer r.Unlock()
+	k := limitKey{kind: kind, key: key, every: every, interval: interval}
+	if rule, ok := r.rules[k]; ok {
+		return rule
+	}
+	if r.rules == nil {
+		r.rules = map[limitKey]*limitRule{}
+	}
+	if len(r.rules) >= minLimitSweep && len(r.rules) >= 2*r.sweep {
+		r.evict()
+	}
+	rule := &limitRule{key: k, once: kind == "once", every: every, interval: interval}
+	r.rules[k] = rule
+	return rule
+}
+
+// evict removes the rules that have expired.
+// The caller must hold the lock.
+func (r *limitRules) evict() {
+	now := r.now()
+	for k, rule := range r.rules {
+		if rule.expired(now) {
+			delete(r.rules, k)
+		}
+	}
+	r.sweep = len(r.rules)
+}
+
+// allow returns true if a message written through a view with the given rule should be written and counts the message.
+// The rule is looked up by its key, so a view whose rule was evicted uses the rule created for the key since,
+// or adds its own rule back, which is expired and so behaves the same as a new rule.
+func (r *limitRules) allow(rule *limitRule) bool {
+	r.Lock()
+	defer r.Unlock()
+	current, ok := r.rules[rule.key]
+	if !ok {
+		if r.rules == nil {
+			r.rules = map[limitKey]*limitRule{}
+		}
+		r.rules[rule.key] = rule
+		current = rule
+	}
+	return current.allow(r.now())
+}
@@ -0,0 +1,78 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxLineLength is the maximum length of an incomplete line buffered by a lineWriter.
+const maxLineLength = 64 * 1024
+
+// lineWriter is an io.Writer that splits the bytes written to it into lines
+// and writes each line as a message at a fixed level.
+// Incomplete lines are buffered until a newline is written or the writer is closed.
+// An incomplete line longer than maxLineLength is written as a message, so the buffer is bounded.
+type lineWriter struct {
+	logger *Logger
+	level  Level
+	mutex  sync.Mutex
+	buffer []byte
+}
+
+// Write writes each complete line in p as a message and buffers the remainder.
+// Carriage returns at the end of lines and empty lines are ignored.
+// If a message cannot be written, then returns the number of bytes consumed, including the line that failed, and the error.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	n := 0
+	for {
+		i := bytes.IndexByte(p[n:], '\n')
+		if i == -1 {
+			break
+		}
+		line := p[n : n+i]
+		if len(w.buffer) > 0 {
+			line = append(w.buffer, line...)
+		}
+		// reclaim the space used by the incomplete line
+		w.buffer = nil
+		n += i + 1
+		if err := w.writeLine(line); err != nil {
+			return n, err
+		}
+	}
+	w.buffer = append(w.buffer, p[n:]...)
+	if len(w.buffer) > maxLineLength {
+		line := w.buffer
+		w.buffer = nil
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes the buffered incomplete line, if any, as a message.
+func (w *lineWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	line := w.buffer
+	w.buffer = nil
+	return w.writeLine(line)
+}
+
+func (w *lineWriter) writeLine(line []byte) error {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) == 0 {
+		return nil
+	}
+	return w.logger.write(w.level, string(line))
+}
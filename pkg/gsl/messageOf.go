@@ -0,0 +1,37 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+)
+
+// messageOf returns the message of the object, which is used to group messages, e.g., for sampling.
+// The message of a map is the value of the message field and the message of an error is its text.
+// Other objects that are not strings are grouped by type.
+func (l *Logger) messageOf(obj interface{}) string {
+	switch o := obj.(type) {
+	case string:
+		return o
+	case error:
+		return o.Error()
+	case *fieldRecord:
+		return o.msg
+	case record:
+		return fmt.Sprint(o[l.MessageField])
+	case map[string]interface{}:
+		if msg, ok := o[l.MessageField]; ok {
+			return fmt.Sprint(msg)
+		}
+	case map[string]string:
+		if msg, ok := o[l.MessageField]; ok {
+			return msg
+		}
+	}
+	return fmt.Sprintf("%T", obj)
+}
@@ -0,0 +1,65 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// priorityQueue is an entryQueue with a ring buffer for each level.
+// Entries at more severe levels are removed first and the entries at each level are removed in order,
+// so a full queue of debug messages does not delay or block error messages.
+type priorityQueue struct {
+	levels [LevelFatal + 1]*ringBuffer
+	count  int
+}
+
+// newPriorityQueue returns a new priority queue that holds up to size entries for each level.
+func newPriorityQueue(size int) *priorityQueue {
+	q := &priorityQueue{}
+	for i := range q.levels {
+		q.levels[i] = newRingBuffer(size)
+	}
+	return q
+}
+
+// ring returns the ring buffer for the level.
+// Levels out of range use the ring buffer for the nearest level.
+func (q *priorityQueue) ring(level Level) *ringBuffer {
+	if level < LevelTrace {
+		return q.levels[LevelTrace]
+	}
+	if level > LevelFatal {
+		return q.levels[LevelFatal]
+	}
+	return q.levels[level]
+}
+
+func (q *priorityQueue) len() int {
+	return q.count
+}
+
+func (q *priorityQueue) full(level Level) bool {
+	return q.ring(level).full(level)
+}
+
+func (q *priorityQueue) push(e entry) {
+	q.ring(e.level).push(e)
+	q.count++
+}
+
+func (q *priorityQueue) pop() entry {
+	for i := len(q.levels) - 1; i >= 0; i-- {
+		if q.levels[i].len() > 0 {
+			q.count--
+			return q.levels[i].pop()
+		}
+	}
+	return entry{}
+}
+
+func (q *priorityQueue) dropOldest(level Level) entry {
+	q.count--
+	return q.ring(level).pop()
+}
@@ -0,0 +1,177 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"strings"
+	"time"
+)
+
+// record is a map built by the logger itself, e.g., by Log or Infow.
+// Unlike maps passed in by callers, a record is owned by the logger and can be modified in place by FormatObject.
+type record map[string]interface{}
+
+// newRecord returns the object to serialize in the given format and its header for the given level and object.
+// Objects are copied into a new map with the level, timestamp, and bound fields, so objects passed in by callers are never modified.
+// The fields of structs and maps are added to the record, while other objects, such as slices and numbers, are added under the object field.
+// If the object field is empty, then other objects are returned as is, unless the logger has bound fields.
+func (l *Logger) newRecord(level string, obj interface{}, format string) (interface{}, []interface{}) {
+	if r, ok := obj.(*fieldRecord); ok {
+		obj = r.record(l.MessageField)
+	}
+	var m map[string]interface{}
+	switch o := obj.(type) {
+	case record:
+		m = map[string]interface{}(o)
+		for _, k := range []string{l.LevelField, l.TimeStampField} {
+			if v, ok := m[k]; ok && len(k) > 0 && l.Collision != CollisionOverwrite {
+				delete(m, k)
+				l.setField(m, k, v)
+			}
+		}
+		for k, v := range l.fields {
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+	case error:
+		m = l.boundFields()
+		if len(l.MessageField) > 0 {
+			m[l.MessageField] = strings.Replace(o.Error(), "\n", ": ", -1)
+		}
+		l.addErrorFields(m, o, format)
+	case string:
+		m = l.boundFields()
+		if len(l.MessageField) > 0 {
+			m[l.MessageField] = o
+		}
+	case map[string]string:
+		m = l.boundFields()
+		for k, v := range o {
+			if !l.collides(k) {
+				m[k] = v
+			}
+		}
+		for _, k := range []string{l.LevelField, l.TimeStampField} {
+			if v, ok := o[k]; ok && len(k) > 0 {
+				l.setField(m, k, v)
+			}
+		}
+	case map[string]interface{}:
+		m = l.boundFields()
+		l.addFields(m, o)
+	default:
+		if len(l.ObjectField) == 0 && len(l.fields) == 0 {
+			return obj, nil
+		}
+		m = l.boundFields()
+		if values, ok := structToMap(obj); ok {
+			l.addFields(m, values)
+		} else if len(l.ObjectField) > 0 {
+			m[l.ObjectField] = obj
+		} else if len(l.MessageField) > 0 {
+			m[l.MessageField] = obj
+		}
+	}
+	h := make([]interface{}, 0, 3)
+	if len(l.LevelField) > 0 {
+		m[l.LevelField] = level
+		h = append(h, l.LevelField)
+	}
+	if len(l.TimeStampField) > 0 {
+		m[l.TimeStampField] = l.now().Format(l.TimeStampFormat)
+		h = append(h, l.TimeStampField)
+	}
+	if _, ok := m[l.MessageField]; ok && len(l.MessageField) > 0 {
+		h = append(h, l.MessageField)
+	}
+	return m, h
+}
+
+// now returns the timestamp for a message, which is the current time unless the logger has a pinned timestamp.
+func (l *Logger) now() time.Time {
+	if !l.timeStamp.IsZero() {
+		return l.timeStamp
+	}
+	return time.Now()
+}
+
+// collides returns true if the key is used by the logger for the level or timestamp field.
+func (l *Logger) collides(k string) bool {
+	return len(k) > 0 && (k == l.LevelField || k == l.TimeStampField)
+}
+
+// addFields adds the fields provided by the caller to the record.
+// Fields that collide with the level or timestamp field are added last,
+// so the collision policy does not depend on the order the fields are iterated.
+func (l *Logger) addFields(m map[string]interface{}, fields map[string]interface{}) {
+	for k, v := range fields {
+		if !l.collides(k) {
+			m[k] = v
+		}
+	}
+	for _, k := range []string{l.LevelField, l.TimeStampField} {
+		if v, ok := fields[k]; ok && len(k) > 0 {
+			l.setField(m, k, v)
+		}
+	}
+}
+
+// setField sets a field provided by the caller in the record.
+// If the key is used by the logger for the level or timestamp field, then the collision policy is applied.
+// The other fields must already be set in the record, so the policy can avoid overwriting them.
+// With CollisionPrefix, the key is prefixed again until it is unused, e.g., "fields.fields.level".
+// With CollisionNest, the value is set in a new map that copies the map under the fields key, if any,
+// since that map may be owned by the caller.  If the fields key holds a value other than a map, then the key is prefixed.
+func (l *Logger) setField(m map[string]interface{}, k string, v interface{}) {
+	if !l.collides(k) {
+		m[k] = v
+		return
+	}
+	switch l.Collision {
+	case CollisionPrefix:
+		l.setPrefixed(m, k, v)
+	case CollisionNest:
+		existing, ok := m[l.FieldsKey]
+		if !ok {
+			m[l.FieldsKey] = map[string]interface{}{k: v}
+			return
+		}
+		fields, ok := existing.(map[string]interface{})
+		if !ok {
+			l.setPrefixed(m, k, v)
+			return
+		}
+		nested := make(map[string]interface{}, len(fields)+1)
+		for fk, fv := range fields {
+			nested[fk] = fv
+		}
+		nested[k] = v
+		m[l.FieldsKey] = nested
+	default:
+		m[k] = v
+	}
+}
+
+// setPrefixed sets the value under the key prefixed with the fields key until the key is unused.
+func (l *Logger) setPrefixed(m map[string]interface{}, k string, v interface{}) {
+	k = l.FieldsKey + "." + k
+	for _, ok := m[k]; ok; _, ok = m[k] {
+		k = l.FieldsKey + "." + k
+	}
+	m[k] = v
+}
+
+// boundFields returns a new map containing the fields bound to the logger.
+func (l *Logger) boundFields() map[string]interface{} {
+	m := make(map[string]interface{}, len(l.fields)+3)
+	for k, v := range l.fields {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,59 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+// entry is a formatted message waiting in a queue.
+type entry struct {
+	level Level  // the level of the message
+	line  string // the formatted message without a trailing newline
+}
+
+// ringBuffer is an entryQueue that removes entries in the order they were added regardless of level.
+// A ringBuffer is not safe for concurrent use.
+type ringBuffer struct {
+	entries []entry
+	head    int // position of the oldest entry
+	count   int // number of entries in the buffer
+}
+
+// newRingBuffer returns a new ring buffer that holds up to size entries.
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]entry, size)}
+}
+
+// len returns the number of entries in the buffer.
+func (r *ringBuffer) len() int {
+	return r.count
+}
+
+// full returns true if the buffer cannot hold another entry at any level.
+func (r *ringBuffer) full(level Level) bool {
+	return r.count == len(r.entries)
+}
+
+// push adds the entry to the end of the buffer.
+// The caller must check that the buffer is not full.
+func (r *ringBuffer) push(e entry) {
+	r.entries[(r.head+r.count)%len(r.entries)] = e
+	r.count++
+}
+
+// pop removes and returns the oldest entry in the buffer.
+// The caller must check that the buffer is not empty.
+func (r *ringBuffer) pop() entry {
+	e := r.entries[r.head]
+	r.entries[r.head] = entry{}
+	r.head = (r.head + 1) % len(r.entries)
+	r.count--
+	return e
+}
+
+// dropOldest removes and returns the oldest entry in the buffer regardless of level.
+func (r *ringBuffer) dropOldest(level Level) entry {
+	return r.pop()
+}
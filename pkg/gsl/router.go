@@ -0,0 +1,98 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"sync"
+)
+
+// router contains the routing of levels to writers that is shared by a logger and its children.
+// The read lock is held while writing a message, so the routing cannot change in the middle of a write.
+type router struct {
+	sync.RWMutex
+	levels        map[string]int // level --> position in writers
+	disabled      map[string]int // disabled level --> previous position in writers
+	writers       []Writer       // list of writers
+	formats       []string       // list of formats for each writer
+	minLevel      Level          // messages below the minimum level are dropped
+	verbose       bool           // verbose mode is on
+	verboseLevels map[string]int // levels only routed in verbose mode --> position in writers
+	destinations  []destination  // list of destinations for each writer, used for reopening
+	async         *AsyncInput    // configuration of async mode, nil if messages are written synchronously
+	dropped       *dropCounter   // number of messages dropped in async mode
+	sampler       *sampler       // sampler of messages, nil if messages are not sampled
+	deduper       *deduper       // suppressor of duplicate messages, nil if duplicates are written
+}
+
+// destination is the resource a writer was created from.
+type destination struct {
+	uri         string // the uri of the resource
+	compression string // the compression algorithm
+}
+
+// newRouter returns a new router with a copy of the given levels, writers, and formats.
+func newRouter(levels map[string]int, writers []Writer, formats []string) *router {
+	r := &router{
+		levels:        make(map[string]int, len(levels)),
+		disabled:      map[string]int{},
+		writers:       make([]Writer, len(writers)),
+		formats:       make([]string, len(formats)),
+		verboseLevels: map[string]int{},
+		dropped:       &dropCounter{},
+	}
+	for level, position := range levels {
+		r.levels[level] = position
+	}
+	copy(r.writers, writers)
+	copy(r.formats, formats)
+	return r
+}
+
+// route is the routing of a single level.
+type route struct {
+	position int  // position in writers
+	enabled  bool // level is routed to the writer at position
+	disabled bool // level was disabled and remembers position
+}
+
+// route returns the routing of the given level.
+// The caller must hold the lock.
+func (r *router) route(level string) route {
+	if position, ok := r.levels[level]; ok {
+		return route{position: position, enabled: true}
+	}
+	if position, ok := r.disabled[level]; ok {
+		return route{position: position, disabled: true}
+	}
+	return route{}
+}
+
+// setRoute restores the routing of the given level to a value previously returned by route.
+// The caller must hold the lock.
+func (r *router) setRoute(level string, rt route) {
+	delete(r.levels, level)
+	delete(r.disabled, level)
+	if rt.enabled {
+		r.levels[level] = rt.position
+	} else if rt.disabled {
+		r.disabled[level] = rt.position
+	}
+}
+
+// wrap returns the writer wrapped in an asyncWriter if async mode is on.
+// If async mode is off or the writer is already wrapped, then returns the writer itself.
+// The caller must hold the lock.
+func (r *router) wrap(w Writer) Writer {
+	if r.async == nil {
+		return w
+	}
+	if _, ok := w.(*asyncWriter); ok {
+		return w
+	}
+	return newAsyncWriter(w, r.async, r.dropped)
+}
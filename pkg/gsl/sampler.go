@@ -0,0 +1,187 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// samplerKey is the level and message that messages are sampled by.
+type samplerKey struct {
+	level Level
+	msg   string
+}
+
+// samplerCount is the number of messages written and sampled out for a key in the current interval.
+type samplerCount struct {
+	seen    int
+	dropped int
+}
+
+// sampler decides which messages are written when sampling and periodically writes a summary of the messages sampled out.
+// The sampler is shared by a logger and its children.
+type sampler struct {
+	sync.Mutex
+	logger   *Logger // the logger that writes the summaries
+	input    SamplingInput
+	levels   map[Level]bool               // the levels that are sampled, nil for all levels
+	counts   map[samplerKey]*samplerCount // the counts for the current interval
+	overflow map[Level]*samplerCount      // the counts by level for messages past the number of keys
+	start    time.Time                    // the start of the current interval
+	clock    func() time.Time             // returns the current time, defaults to time.Now
+	done     chan struct{}                // closed to stop the goroutine
+	wg       sync.WaitGroup               // done when the goroutine has stopped
+	once     sync.Once
+}
+
+// newSampler returns a new sampler and starts its goroutine, which writes the summaries using the given logger.
+func newSampler(logger *Logger, input SamplingInput) *sampler {
+	if input.Interval <= 0 {
+		input.Interval = DefaultSamplingInterval
+	}
+	if input.Keys <= 0 {
+		input.Keys = DefaultSamplingKeys
+	}
+	s := &sampler{
+		logger:   logger,
+		input:    input,
+		counts:   map[samplerKey]*samplerCount{},
+		overflow: map[Level]*samplerCount{},
+		start:    time.Now(),
+		clock:    time.Now,
+		done:     make(chan struct{}),
+	}
+	if len(input.Levels) > 0 {
+		s.levels = make(map[Level]bool, len(input.Levels))
+		for _, level := range input.Levels {
+			s.levels[level] = true
+		}
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// run writes a summary at the end of each interval until the sampler is stopped.
+func (s *sampler) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.input.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.summarize(true)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// allow returns true if the message should be written and counts the message.
+// Once the number of keys in the interval is reached, messages without a key are counted in the overflow count for their level.
+func (s *sampler) allow(level Level, msg string) bool {
+	if s.levels != nil && !s.levels[level] {
+		return true
+	}
+	s.Lock()
+	defer s.Unlock()
+	key := samplerKey{level: level, msg: msg}
+	c, ok := s.counts[key]
+	if !ok {
+		if len(s.counts) < s.input.Keys {
+			c = &samplerCount{}
+			s.counts[key] = c
+		} else if c, ok = s.overflow[level]; !ok {
+			c = &samplerCount{}
+			s.overflow[level] = c
+		}
+	}
+	c.seen++
+	if c.seen <= s.input.First {
+		return true
+	}
+	if s.input.Thereafter > 0 && (c.seen-s.input.First)%s.input.Thereafter == 0 {
+		return true
+	}
+	c.dropped++
+	return false
+}
+
+// summarize starts a new interval and writes a summary message for each key with messages sampled out in the last interval.
+// Each summary is written at the level of the key and includes the number of messages sampled out and the sampled message.
+// The messages sampled out from the overflow count of a level are summarized without a sampled message.
+// If full is true, then the interval ended on schedule, otherwise the interval was ended early and the summary reports the time elapsed.
+// Summaries are not sampled.
+func (s *sampler) summarize(full bool) {
+	s.Lock()
+	end := s.start.Add(s.input.Interval)
+	if !full {
+		end = s.clock()
+	}
+	elapsed := end.Sub(s.start)
+	s.start = end
+	counts, overflow := s.counts, s.overflow
+	s.counts = map[samplerKey]*samplerCount{}
+	s.overflow = map[Level]*samplerCount{}
+	s.Unlock()
+	keys := make([]samplerKey, 0, len(counts))
+	for k, c := range counts {
+		if c.dropped > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+	for _, k := range keys {
+		dropped := counts[k].dropped
+		m := record{
+			"sampled":         dropped,
+			"sampled.message": k.msg,
+		}
+		if len(s.logger.MessageField) > 0 {
+			m[s.logger.MessageField] = fmt.Sprintf("sampled out %d messages in the last %s", dropped, elapsed)
+		}
+		s.logger.output(k.level, m, false) // #nosec
+	}
+	levels := make([]Level, 0, len(overflow))
+	for level, c := range overflow {
+		if c.dropped > 0 {
+			levels = append(levels, level)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i] < levels[j]
+	})
+	for _, level := range levels {
+		dropped := overflow[level].dropped
+		m := record{
+			"sampled": dropped,
+		}
+		if len(s.logger.MessageField) > 0 {
+			m[s.logger.MessageField] = fmt.Sprintf("sampled out %d other messages in the last %s", dropped, elapsed)
+		}
+		s.logger.output(level, m, false) // #nosec
+	}
+}
+
+// stop stops the goroutine and then writes the summary for the current interval, which ends early.
+// The caller must not hold the router lock.
+func (s *sampler) stop() {
+	s.once.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		s.summarize(false)
+	})
+}
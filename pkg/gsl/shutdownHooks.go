@@ -0,0 +1,37 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"sync"
+)
+
+// shutdownHooks is the registry of functions run by Fatal before exiting.
+// The registry is shared by a logger and its children.
+type shutdownHooks struct {
+	sync.Mutex
+	hooks []func() error
+}
+
+// add appends the given hook to the registry.
+func (s *shutdownHooks) add(hook func() error) {
+	s.Lock()
+	s.hooks = append(s.hooks, hook)
+	s.Unlock()
+}
+
+// list returns a copy of the hooks in reverse order of registration.
+func (s *shutdownHooks) list() []func() error {
+	s.Lock()
+	defer s.Unlock()
+	hooks := make([]func() error, 0, len(s.hooks))
+	for i := len(s.hooks) - 1; i >= 0; i-- {
+		hooks = append(hooks, s.hooks[i])
+	}
+	return hooks
+}
@@ -0,0 +1,30 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"bytes"
+	"hash/fnv"
+)
+
+// signature returns a hash of the formatted line with the timestamp removed, so copies of a message written at different times have the same signature.
+// The line must be formatted with the timestamp pinned to the logger, so the timestamp can be found in the line.
+func (l *Logger) signature(line []byte) uint64 {
+	h := fnv.New64a()
+	if len(l.TimeStampField) > 0 && !l.timeStamp.IsZero() {
+		var buf [64]byte
+		ts := l.timeStamp.AppendFormat(buf[:0], l.TimeStampFormat)
+		if i := bytes.Index(line, ts); len(ts) > 0 && i >= 0 {
+			h.Write(line[:i])         // #nosec
+			h.Write(line[i+len(ts):]) // #nosec
+			return h.Sum64()
+		}
+	}
+	h.Write(line) // #nosec
+	return h.Sum64()
+}
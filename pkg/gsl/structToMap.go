@@ -0,0 +1,138 @@
+// =================================================================
+//
+// Copyright (C) 2019 Spatial Current, Inc. - All Rights Reserved
+// Released as open source under the MIT License.  See LICENSE file.
+//
+// =================================================================
+
+package gsl
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// structToMap converts a struct, a map with string keys, or a pointer to either into a map of fields.
+// For structs, only exported fields are included and field names are taken from the `gss` struct tag or the `json` struct tag, in that order.
+// Fields with the tag "-" are skipped, fields with the "omitempty" option are skipped if empty, and embedded structs without a tag are flattened, unless nil.
+// Types that serialize themselves, such as time.Time, are not converted, including types that serialize themselves through a pointer receiver.
+// If the object cannot be converted, then returns false.
+func structToMap(obj interface{}) (map[string]interface{}, bool) {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() {
+		return nil, false
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if isMarshaler(v.Type()) {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			m[k.String()] = v.MapIndex(k).Interface()
+		}
+		return m, true
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		addStructFields(m, v)
+		return m, true
+	}
+	return nil, false
+}
+
+// addStructFields adds the exported fields of the struct value to the map.
+func addStructFields(m map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Anonymous && len(name) == 0 {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+				continue // skip nil embedded structs, like encoding/json
+			}
+			if fv.Kind() == reflect.Struct {
+				addStructFields(m, fv)
+				continue
+			}
+		}
+		if len(f.PkgPath) > 0 {
+			continue // skip unexported fields
+		}
+		if omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		m[name] = fv.Interface()
+	}
+}
+
+// isMarshaler returns true if the type or a pointer to the type serializes itself.
+func isMarshaler(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) || pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType)
+}
+
+// fieldName returns the name of the struct field from its tags and whether the field has the "omitempty" option.
+// If the field is skipped with the tag "-", then returns false.
+func fieldName(f reflect.StructField) (string, bool, bool) {
+	for _, key := range []string{"gss", "json"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			if tag == "-" {
+				return "", false, false
+			}
+			parts := strings.Split(tag, ",")
+			omitEmpty := false
+			for _, option := range parts[1:] {
+				if option == "omitempty" {
+					omitEmpty = true
+				}
+			}
+			return parts[0], omitEmpty, true
+		}
+	}
+	return "", false, true
+}
+
+// isEmptyValue returns true if the value is empty as defined by the "omitempty" option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}